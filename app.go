@@ -2,98 +2,289 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"log/slog"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
 )
 
 // App holds the application state
 type App struct {
-	AI             *AI
-	Deck           []Flashcard
+	AI             *chinese.AI
+	Deck           []chinese.Flashcard
 	CurrentCardIdx int
-	Revealed       bool
+	RevealStep     int
+	RevealStyle    RevealStyle
+	ShowNotes      bool
+	ShowSpoken     bool
+	ShowStrokes    bool
+	Reverse        bool
+	Privacy        bool
+	Unmasked       bool
 	Application    *tview.Application
 	MainView       *tview.Flex
 	CardView       *tview.TextView
+	StatusBar      *tview.TextView
 	FlashcardsFile string
+	DeckURL        string
+	DeckFiles      []string
+	Durable        bool
+	Config         *Config
+	ConfigFile     string
+	Theme          Theme
+	Audio          *AudioLibrary
+	Logger         *slog.Logger
+	State          *State
+	StateFile      string
+	Status         string
+	FilterName     string
+	TargetCount    int
+	Decompositions map[string]Decomposition
+
+	// NewCardPosition is where new cards are added to the file, NewCardAppend or NewCardPrepend
+	NewCardPosition string
+
+	// cardWidth is the inner width of the card view as of the last draw
+	cardWidth int
+
+	// compact is set while the terminal is too small for the centered layout
+	compact bool
+
+	// undoStack and redoStack hold previous deck states, guarded by mu
+	undoStack [][]chinese.Flashcard
+	redoStack [][]chinese.Flashcard
+
+	// autoplay is the running auto-play session, or nil
+	autoplay *autoplay
+
+	// queue is the review order of this session, guarded by mu
+	queue reviewQueue
+
+	// cardFiles maps the IDs of the cards of a combined deck to the file they came from
+	cardFiles map[int]string
+
+	// renumbered holds the IDs of the cards of a combined deck given a new ID for the session
+	renumbered map[int]bool
+
+	// previousReveal is the reveal style to go back to when character practice is turned off
+	previousReveal RevealStyle
+
+	// templates replace the card view layout, nil if no templates are configured
+	templates *cardTemplates
+
+	// segmenter splits the Chinese into words, nil if word segmentation is off
+	segmenter *Segmenter
+
+	// order picks the next card among the queued ones instead of the queue order, if set
+	order *weightedOrder
+
+	// session counts the cards reviewed and added since startup
+	session session
+
+	// dirty is set when the deck in memory differs from the flashcards file
+	dirty bool
+
+	// fileStamp identifies the flashcards file as last loaded or written, guarded by mu
+	fileStamp fileStamp
+
+	// savedDeck holds the cards of the flashcards file as last loaded or written, guarded
+	// by mu. Reloading a changed file merges the changes on both sides since then.
+	savedDeck []chinese.Flashcard
+
+	// mu guards Deck, CurrentCardIdx, dirty and writes to the flashcards file
+	mu sync.RWMutex
 }
 
+// ErrCardNotFound is returned when no card has the requested ID
+var ErrCardNotFound = errors.New("card not found")
+
+// ErrTranslationFailed is returned when adding a card fails because the text couldn't be translated
+var ErrTranslationFailed = errors.New("translating text")
+
 // NewApp creates a new application instance
 func NewApp(apiKey, model string) *App {
 	return &App{
-		AI:             NewAI(apiKey, model),
-		Deck:           make([]Flashcard, 0),
+		AI:             chinese.NewAI(apiKey, model),
+		Deck:           make([]chinese.Flashcard, 0),
 		CurrentCardIdx: 0,
-		Revealed:       false,
+		RevealStep:     0,
+		RevealStyle:    RevealAll,
 		Application:    tview.NewApplication(),
+		Config:         &Config{},
+		Theme:          Themes[DefaultThemeName],
+		Audio:          NewAudioLibrary("", nil),
+		Logger:         discardLogger,
+		State:          &State{StudyDays: make(map[string]int)},
+		session:        session{start: time.Now()},
 	}
 }
 
-// LoadDeck loads flashcards from a JSONL file
-func (a *App) LoadDeck(filename string) error {
-	a.FlashcardsFile = filename
-	file, err := os.Open(filename)
-	if err != nil {
-		return err
+// SaveNewCard translates the English text, adds the resulting card and returns to the main view.
+// If the English has several meanings, the user picks which ones to add. If a card with the
+// same English is already in the deck, the user is asked first whether to add it anyway.
+func (a *App) SaveNewCard(englishText, source string) {
+	if idx := a.findEnglish(englishText); idx >= 0 {
+		a.ShowDuplicateDialog(englishText, idx, func() {
+			a.saveNewCard(englishText, source)
+		})
+		return
 	}
-	defer file.Close()
+	a.saveNewCard(englishText, source)
+}
 
-	decoder := json.NewDecoder(file)
-	for decoder.More() {
-		var card Flashcard
-		if err := decoder.Decode(&card); err != nil {
-			return err
-		}
-		a.Deck = append(a.Deck, card)
-	}
-	return nil
+// saveNewCard translates and adds the English text without checking for duplicates.
+// The translation runs in the background, as retries after rate limits can take a minute.
+func (a *App) saveNewCard(englishText, source string) {
+	a.showProgress(" Adding Card ", "\nTranslating\n\n"+englishText)
+	go func() {
+		defer a.recoverPanic()
+		translation, err := a.AI.Translate(englishText)
+		a.Application.QueueUpdateDraw(func() {
+			a.addTranslatedCard(englishText, source, translation, err)
+		})
+	}()
 }
 
-// SaveNewCard appends the new card to the deck and writes it to the file
-func (a *App) SaveNewCard(englishText string) {
-	zh, pinyin, err := a.AI.Translate(englishText)
+// addTranslatedCard adds the card for a translation made by saveNewCard, or reports why
+// the translation failed
+func (a *App) addTranslatedCard(englishText, source string, translation chinese.Translation, err error) {
 	if err != nil {
-		a.Application.Stop()
-		fmt.Println("Error translating text:", err)
+		a.Application.SetRoot(a.MainView, true)
+		a.SetStatus(a.errorStatus("Error translating card", err))
 		return
 	}
 
-	newCard := Flashcard{
-		ID:      len(a.Deck) + 1,
-		English: englishText,
-		Chinese: zh,
-		Pinyin:  pinyin,
+	if len(translation.Senses) > 1 {
+		a.ShowSensePicker(englishText, source, translation)
+		return
 	}
-	a.Deck = append(a.Deck, newCard)
 
-	// Append the new card to the flashcards file
-	file, err := os.OpenFile(a.FlashcardsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	card := translation.Card(englishText)
+	card.Source = source
+	card, err = a.insertCard(card)
 	if err != nil {
-		a.Application.Stop()
-		fmt.Println("Error opening flashcards file:", err)
+		a.Application.SetRoot(a.MainView, true)
+		a.SetStatus(a.errorStatus("Error adding card", err))
 		return
 	}
-	defer file.Close()
 
-	cardJSON, err := json.Marshal(newCard)
+	a.Application.SetRoot(a.MainView, true)
+	if warning := a.latinWarning(card); warning != "" {
+		a.Status = warning
+	}
+	a.UpdateCardView()
+}
+
+// AddCard translates the English text, appends the new card to the deck and writes it to the file.
+// Ambiguous English is added with its most common meaning.
+func (a *App) AddCard(englishText, source string) (chinese.Flashcard, error) {
+	translation, err := a.AI.Translate(englishText)
 	if err != nil {
-		a.Application.Stop()
-		fmt.Println("Error marshaling new card:", err)
-		return
+		return chinese.Flashcard{}, fmt.Errorf("%w: %w", ErrTranslationFailed, err)
 	}
-	if _, err := file.Write(append(cardJSON, '\n')); err != nil {
-		a.Application.Stop()
-		fmt.Println("Error writing new card to file:", err)
-		return
+	card := translation.Card(englishText)
+	card.Source = source
+	return a.insertCard(card)
+}
+
+// insertCard gives the card a new ID and creation time, adds it to the deck and writes it to the file,
+// at the end or, with NewCardPrepend, at the start. The card is queued for review according to the
+// new_cards setting.
+func (a *App) insertCard(card chinese.Flashcard) (chinese.Flashcard, error) {
+	err := a.mutateDeck(func() error {
+		if err := a.checkCanAdd(); err != nil {
+			return err
+		}
+		a.snapshot()
+		card.ID = a.nextID()
+		if card.CreatedAt == nil {
+			now := time.Now().Truncate(time.Second)
+			card.CreatedAt = &now
+		}
+		if a.NewCardPosition == NewCardPrepend {
+			a.Deck = slices.Insert(a.Deck, 0, card)
+			if len(a.Deck) > 1 {
+				a.CurrentCardIdx++
+			}
+			a.queueNewCard(card.ID)
+			a.session.added++
+			// Lines can only be appended, so the file is rewritten to put the card first
+			return a.rewriteDeck()
+		}
+		a.Deck = append(a.Deck, card)
+		a.queueNewCard(card.ID)
+		a.session.added++
+		return a.appendCard(card)
+	})
+	if err != nil {
+		return chinese.Flashcard{}, err
 	}
+	a.Logger.Info("card added", "id", card.ID, "english", card.English)
+	return card, nil
+}
 
-	a.Application.SetRoot(a.MainView, true)
-	a.UpdateCardView()
+// DeleteCard removes the card with the given ID from the deck and rewrites the file
+func (a *App) DeleteCard(id int) error {
+	return a.mutateDeck(func() error {
+		if err := a.checkWritable(); err != nil {
+			return err
+		}
+		idx := a.cardIndex(id)
+		if idx < 0 {
+			return ErrCardNotFound
+		}
+		a.snapshot()
+		a.Logger.Info("card deleted", "id", id)
+		a.Deck = append(a.Deck[:idx:idx], a.Deck[idx+1:]...)
+		if a.CurrentCardIdx >= len(a.Deck) {
+			a.CurrentCardIdx = 0
+		}
+		return a.rewriteDeck()
+	})
+}
+
+// readDeck calls fn while holding the deck read lock
+func (a *App) readDeck(fn func()) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	fn()
+}
+
+// mutateDeck calls fn while holding the deck write lock
+func (a *App) mutateDeck(fn func() error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return fn()
+}
+
+// cardIndex returns the position of the card with the given ID in the deck, or -1
+func (a *App) cardIndex(id int) int {
+	for i, card := range a.Deck {
+		if card.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextID returns an ID greater than any card in the deck
+func (a *App) nextID() int {
+	id := 0
+	for _, card := range a.Deck {
+		if card.ID > id {
+			id = card.ID
+		}
+	}
+	return id + 1
 }
 
 // SetupUI initializes the user interface
@@ -109,74 +300,279 @@ func (a *App) SetupUI() {
 	// Style the card view
 	a.CardView.SetBorder(true).
 		SetTitle(" Chinese Learning Cards ").
-		SetTitleAlign(tview.AlignCenter)
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.GetColor(a.Theme.Border))
 
-	// Set up the main view
-	a.MainView = tview.NewFlex().
-		AddItem(nil, 0, 1, false).
-		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
-			AddItem(nil, 0, 1, false).
-			AddItem(a.CardView, 0, 2, true).
-			AddItem(nil, 0, 1, false), 0, 2, true).
-		AddItem(nil, 0, 1, false)
+	// Re-render the card when the terminal is resized so the divider spans the card
+	a.CardView.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		// The card view has a border, so its inner area is one cell smaller on each side,
+		// except in the compact layout
+		if !a.compact {
+			x, y, width, height = x+1, y+1, width-2, height-2
+		}
+		if width != a.cardWidth {
+			a.cardWidth = width
+			a.UpdateCardView()
+		}
+		return x, y, width, height
+	})
+
+	a.StatusBar = newStatusBar()
+
+	// Set up the main view, switching to the compact layout on small terminals
+	a.MainView = tview.NewFlex().SetDirection(tview.FlexRow)
+	a.layoutMainView()
+	a.Application.SetBeforeDrawFunc(a.fitLayout)
 
 	a.UpdateCardView()
 }
 
 // UpdateCardView updates the display of the current card
 func (a *App) UpdateCardView() {
-	if len(a.Deck) == 0 {
+	a.updateStatusBar()
+
+	var card chinese.Flashcard
+	var idx, total int
+	a.readDeck(func() {
+		total = len(a.Deck)
+		if total > 0 {
+			idx = a.CurrentCardIdx
+			card = a.Deck[idx]
+		}
+	})
+
+	if total == 0 {
 		a.CardView.SetText("No cards in deck!")
 		return
 	}
+	a.CardView.SetText(a.renderCard(card, idx, total))
+}
 
-	card := a.Deck[a.CurrentCardIdx]
+// renderCard formats the card at position idx of a deck of total cards, with its color tags,
+// as the card view shows it in the current reveal state
+func (a *App) renderCard(card chinese.Flashcard, idx, total int) string {
 	var content strings.Builder
-	content.WriteString("\n\n\n") // Add some padding at the top
-	content.WriteString(fmt.Sprintf("Card %d/%d (ID: %d)\n\n", a.CurrentCardIdx+1, len(a.Deck), card.ID))
+	if a.compact {
+		content.WriteString("[::d]Enlarge the terminal for the full view[::-]\n")
+	} else {
+		content.WriteString("\n\n\n") // Add some padding at the top
+	}
+	content.WriteString(fmt.Sprintf("Card %d/%d (ID: %d)", idx+1, total, card.ID))
+	if a.DeckFiles != nil {
+		var filename string
+		a.readDeck(func() {
+			filename = a.cardFile(card.ID)
+		})
+		content.WriteString(" from " + tview.Escape(filepath.Base(filename)))
+	}
+	if card.Pinned {
+		content.WriteString("  " + tview.Escape("[pinned]"))
+	}
+	if progress := a.targetProgress(); progress != "" {
+		content.WriteString("  (" + progress + ")")
+	}
+	if a.IsDirty() {
+		content.WriteString("  [unsaved changes]")
+	}
+	if a.FilterName != "" {
+		content.WriteString("\nReviewing cards " + tview.Escape(a.FilterName))
+		if card.CreatedAt != nil {
+			content.WriteString(", this one added " + card.CreatedAt.Local().Format(dateLayout))
+		}
+	}
+	if reminder := a.breakReminder(); reminder != "" {
+		content.WriteString("\n" + a.Theme.LabelTag(reminder))
+	}
+	content.WriteString("\n\n")
 
-	// Use colors for highlighting
-	content.WriteString("[::b]English:[::-]\n")
-	content.WriteString("[cyan]" + card.English + "[white]\n\n")
+	if body, ok := a.templateBody(card); ok {
+		content.WriteString(body)
+	} else {
+		// Sections are separated by a blank line, in the configured order
+		var sections []string
+		for _, field := range a.cardViewFields() {
+			if section := a.cardSection(field, card); section != "" {
+				sections = append(sections, section)
+			}
+		}
+		content.WriteString(strings.Join(sections, "\n"))
+	}
 
-	if a.Revealed {
-		content.WriteString("[::b]Chinese:[::-]\n")
-		content.WriteString("[yellow]" + card.Chinese + "[white]\n\n")
-		content.WriteString("[::b]Pinyin:[::-]\n")
-		content.WriteString("[green]" + card.Pinyin + "[white]\n")
+	if a.Status != "" {
+		content.WriteString("\n" + tview.Escape(a.Status) + "\n")
 	}
 
-	content.WriteString("\n─────────────────────────\n")
-	content.WriteString("\nControls:\n")
-	content.WriteString("→: Reveal/Next Card  |  n: New Card  |  q: Quit")
+	if a.compact {
+		content.WriteString("\nPress ? for controls")
+	} else if !a.Config.HideControls {
+		content.WriteString("\n" + a.divider() + "\n")
+		content.WriteString("\nControls:\n")
+		content.WriteString(controlsSummary())
+	}
 
-	a.CardView.SetText(content.String())
+	return content.String()
+}
+
+// divider returns a horizontal rule spanning the card view
+func (a *App) divider() string {
+	width := a.cardWidth
+	if width <= 0 {
+		// Not drawn yet, the width is adjusted on the first draw
+		width = 25
+	}
+	return strings.Repeat("─", width)
+}
+
+// WriteDeck saves the whole deck to disk and reports the result
+func (a *App) WriteDeck() {
+	if err := a.SaveDeck(); err != nil {
+		a.SetStatus(a.errorStatus("Error saving deck", err))
+		return
+	}
+	a.SetStatus("Saved deck to " + a.FlashcardsFile)
+}
+
+// SetStatus shows a short message on the card view until the next card is shown
+func (a *App) SetStatus(message string) {
+	a.Status = message
+	a.UpdateCardView()
+}
+
+// currentCard returns a copy of the current card, or false if the deck is empty
+func (a *App) currentCard() (chinese.Flashcard, bool) {
+	var card chinese.Flashcard
+	var ok bool
+	a.readDeck(func() {
+		if len(a.Deck) > 0 {
+			card, ok = a.Deck[a.CurrentCardIdx], true
+		}
+	})
+	return card, ok
+}
+
+// Advance reveals the next part of the answer, or moves to the next card once it is fully revealed
+func (a *App) Advance() {
+	if a.RevealStep < a.RevealStyle.Steps() {
+		a.RevealStep++
+		if card, ok := a.currentCard(); ok && a.RevealStep == a.RevealStyle.Steps() {
+			a.RecordReview(card.ID)
+		}
+	} else {
+		a.nextCard(false)
+	}
+	a.UpdateCardView()
+}
+
+// Quit stops auto-play and exits the application
+func (a *App) Quit() {
+	a.StopAutoplay()
+	a.Application.Stop()
 }
 
 // HandleInput processes keyboard input
 func (a *App) HandleInput(event *tcell.EventKey) *tcell.EventKey {
-	if _, ok := a.Application.GetFocus().(*tview.Form); ok {
+	// Only handle shortcuts on the main view, dialogs handle their own input
+	if a.Application.GetFocus() != a.CardView {
 		return event
 	}
-	if _, ok := a.Application.GetFocus().(*tview.InputField); ok {
-		return event
+
+	// While auto-playing, Escape stops it, Ctrl-C, a and q work as usual and other keys
+	// pause and resume
+	if a.autoplay != nil {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			a.StopAutoplay()
+			a.SetStatus("Auto-play off")
+			return nil
+		case event.Key() != tcell.KeyCtrlC && event.Rune() != 'a' && event.Rune() != 'q':
+			a.PauseAutoplay()
+			return nil
+		}
 	}
 
 	switch event.Key() {
+	case tcell.KeyCtrlR:
+		a.RedoChange()
+	case tcell.KeyEscape:
+		a.DismissBreak()
 	case tcell.KeyRight:
-		if !a.Revealed {
-			a.Revealed = true
-		} else {
-			a.Revealed = false
-			a.CurrentCardIdx = (a.CurrentCardIdx + 1) % len(a.Deck)
-		}
-		a.UpdateCardView()
+		a.Advance()
 	case tcell.KeyRune:
 		switch event.Rune() {
 		case 'q':
-			a.Application.Stop()
+			a.Quit()
+		case 'a':
+			a.ToggleAutoplay()
 		case 'n':
 			a.ShowNewCardDialog()
+		case 'k':
+			a.SkipCard()
+		case 'j':
+			a.ShowJumpDialog()
+		case 'L':
+			a.ShowCardList()
+		case 'p':
+			a.PlayCurrentCard()
+		case 'e':
+			a.ShowEditCardDialog()
+		case 'T':
+			a.ShowTagEditor()
+		case 'P':
+			a.ShowPinyinEditor()
+		case '^':
+			a.TogglePin()
+		case 'E':
+			a.ShowExplanation()
+		case 'i':
+			a.ShowCardJSON()
+		case 'H':
+			a.ShowHistory()
+		case 'o':
+			a.ToggleNotes()
+		case 't':
+			a.ToggleSpoken()
+		case 'z':
+			a.ToggleStrokes()
+		case 'r':
+			a.ToggleReverse()
+		case 'f':
+			a.ToggleWeakCards()
+		case 'A':
+			a.ToggleStaleCards()
+		case 'c':
+			a.ToggleHideCharacters()
+		case '1', '2', '3', '4', '5':
+			a.RateConfidence(int(event.Rune() - '0'))
+		case 'R':
+			a.ToggleRecentCards()
+		case 'l':
+			a.ShowLearnMode()
+		case 'm':
+			a.ShowQuiz()
+		case 's':
+			a.ShowStats()
+		case 'S':
+			a.ShowSplitCardDialog()
+		case 'u':
+			a.UndoChange()
+		case 'w':
+			a.WriteDeck()
+		case 'x':
+			a.TogglePrivacy()
+		case 'v':
+			a.ToggleUnmasked()
+		case 'y':
+			a.CopyCurrentCard(false)
+		case 'Y':
+			a.CopyCurrentCard(true)
+		case 'N':
+			a.QuickAddFromClipboard()
+		case 'h':
+			a.ToggleControls()
+		case 'b':
+			a.ToggleStatusBar()
+		case '?':
+			a.ShowHelp()
 		}
 	}
 	return event
@@ -184,32 +580,54 @@ func (a *App) HandleInput(event *tcell.EventKey) *tcell.EventKey {
 
 // ShowNewCardDialog displays the new card input dialog
 func (a *App) ShowNewCardDialog() {
-	var englishInput *tview.InputField
+	var englishInput *tview.TextArea
 
 	form := tview.NewForm()
-	englishInput = tview.NewInputField().
+	englishInput = tview.NewTextArea().
 		SetLabel("English").
-		SetFieldWidth(50)
+		SetPlaceholder("One sentence per line").
+		SetSize(5, 50)
+
+	// Shows why the input was rejected, below the form
+	errorView := tview.NewTextView().SetDynamicColors(true)
 
 	form.AddFormItem(englishInput)
+	form.AddInputField("Source", "", 50, nil, nil)
 	form.AddButton("Save", func() {
-		a.SaveNewCard(englishInput.GetText())
+		lines := splitLines(englishInput.GetText())
+		source := strings.TrimSpace(form.GetFormItemByLabel("Source").(*tview.InputField).GetText())
+		switch len(lines) {
+		case 0:
+			errorView.SetText("[red]Please enter some English text")
+			form.SetFocus(0)
+			a.Application.SetFocus(form)
+		case 1:
+			a.SaveNewCard(lines[0], source)
+		default:
+			a.SaveNewCards(lines, source)
+		}
 	})
 	form.AddButton("Cancel", func() {
 		a.Application.SetRoot(a.MainView, true)
 	})
 
-	form.SetBorder(true).
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(errorView, 1, 0, false)
+	layout.SetBorder(true).
 		SetTitle(" Add New Card ").
 		SetTitleAlign(tview.AlignCenter)
 
-	formFlex := tview.NewFlex().
+	a.Application.SetRoot(centered(layout), true)
+}
+
+// centered wraps a primitive in the flex layout used for dialogs
+func centered(p tview.Primitive) tview.Primitive {
+	return tview.NewFlex().
 		AddItem(nil, 0, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(nil, 0, 1, false).
-			AddItem(form, 0, 1, true).
+			AddItem(p, 0, 1, true).
 			AddItem(nil, 0, 1, false), 0, 2, true).
 		AddItem(nil, 0, 1, false)
-
-	a.Application.SetRoot(formFlex, true)
 }
@@ -0,0 +1,89 @@
+// app_test.go
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"chinese/pkg/chinese"
+)
+
+// update rewrites the golden files with the current output instead of comparing with them
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// checkGolden compares the output with the golden file testdata/name.golden
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	filename := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(filename, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("reading golden file (run go test -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test -update if the change is intended)\ngot:\n%s\nwant:\n%s", filename, got, want)
+	}
+}
+
+func TestRenderCard(t *testing.T) {
+	card := chinese.Flashcard{
+		ID:         7,
+		English:    "I have [two] books",
+		Chinese:    "我有两本书",
+		Pinyin:     "Wǒ yǒu liǎng běn shū",
+		Classifier: "本 (běn)",
+		Notes:      "两 is used before measure words",
+		Source:     "Textbook",
+		Tags:       []string{"HSK 2"},
+	}
+	tests := []struct {
+		name  string
+		setup func(a *App)
+	}{
+		{"front", func(a *App) {}},
+		{"back", func(a *App) {
+			a.RevealStep = 1
+		}},
+		{"reverse_front", func(a *App) {
+			a.Reverse = true
+		}},
+		{"reverse_back", func(a *App) {
+			a.Reverse = true
+			a.RevealStep = 1
+		}},
+		{"privacy_back", func(a *App) {
+			a.Privacy = true
+			a.RevealStep = 1
+		}},
+		{"privacy_reverse_back", func(a *App) {
+			a.Privacy = true
+			a.Reverse = true
+			a.RevealStep = 1
+		}},
+		{"privacy_characters_back", func(a *App) {
+			a.Privacy = true
+			a.RevealStyle = RevealCharacters
+			a.RevealStep = 1
+		}},
+		{"privacy_unmasked", func(a *App) {
+			a.Privacy = true
+			a.Unmasked = true
+			a.RevealStep = 1
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := NewApp("test-key", "test-model")
+			a.Deck = []chinese.Flashcard{card}
+			test.setup(a)
+			checkGolden(t, "render_"+test.name, a.renderCard(card, 0, 3))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// audio.go
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"unicode"
+
+	"chinese/pkg/chinese"
+)
+
+// audioExtensions are the file extensions tried when resolving audio files
+var audioExtensions = []string{".mp3", ".wav", ".ogg"}
+
+// audioPlayers are the command lines tried, in order, when no player is configured
+var audioPlayers = [][]string{
+	{"mpv", "--no-video", "--really-quiet"},
+	{"ffplay", "-nodisp", "-autoexit", "-loglevel", "quiet"},
+	{"afplay"},
+	{"aplay", "-q"},
+}
+
+// ErrNoAudio is returned when no local audio exists for a card
+var ErrNoAudio = errors.New("no local audio for this card")
+
+// AudioLibrary resolves and plays pronunciation audio from a local directory.
+// Files are named after the Chinese text they contain, either a whole word
+// (e.g. 你好.mp3) or a single character (e.g. 你.mp3).
+type AudioLibrary struct {
+	Dir    string
+	Player []string
+
+	// mu guards playing and stops. stops counts the calls to Stop, so a playback
+	// started before one knows to end even if no player process was running yet.
+	mu      sync.Mutex
+	playing *exec.Cmd
+	stops   int
+}
+
+// NewAudioLibrary creates an audio library for the given directory and player command
+func NewAudioLibrary(dir string, player []string) *AudioLibrary {
+	return &AudioLibrary{
+		Dir:    dir,
+		Player: player,
+	}
+}
+
+// Resolve returns the audio files to play, in order, for the given card
+func (l *AudioLibrary) Resolve(card chinese.Flashcard) ([]string, error) {
+	if card.AudioPath != "" {
+		path := card.AudioPath
+		if !filepath.IsAbs(path) && l.Dir != "" {
+			path = filepath.Join(l.Dir, path)
+		}
+		return []string{path}, nil
+	}
+	if l.Dir == "" {
+		return nil, ErrNoAudio
+	}
+
+	// Prefer a recording of the whole text
+	if path := l.lookup(card.Chinese); path != "" {
+		return []string{path}, nil
+	}
+
+	// Otherwise stitch together per-character recordings, skipping punctuation
+	var files []string
+	for _, r := range card.Chinese {
+		if !unicode.Is(unicode.Han, r) {
+			continue
+		}
+		path := l.lookup(string(r))
+		if path == "" {
+			return nil, ErrNoAudio
+		}
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return nil, ErrNoAudio
+	}
+	return files, nil
+}
+
+// lookup returns the path of the audio file for the given text, or "" if there is none
+func (l *AudioLibrary) lookup(text string) string {
+	for _, ext := range audioExtensions {
+		path := filepath.Join(l.Dir, text+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Play plays the files in sequence, stopping any playback already in progress.
+// It blocks until playback finishes or is interrupted.
+func (l *AudioLibrary) Play(files []string) error {
+	player, err := l.player()
+	if err != nil {
+		return err
+	}
+	l.Stop()
+	l.mu.Lock()
+	stops := l.stops
+	l.mu.Unlock()
+
+	for _, file := range files {
+		cmd := exec.Command(player[0], append(player[1:], file)...)
+
+		// The player is started under the lock, so Stop either comes first or can kill it
+		l.mu.Lock()
+		if l.stops != stops {
+			l.mu.Unlock()
+			return nil
+		}
+		err := cmd.Start()
+		if err == nil {
+			l.playing = cmd
+		}
+		l.mu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		err = cmd.Wait()
+
+		l.mu.Lock()
+		interrupted := l.stops != stops
+		if l.playing == cmd {
+			l.playing = nil
+		}
+		l.mu.Unlock()
+
+		if interrupted {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop interrupts the current playback, if any
+func (l *AudioLibrary) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stops++
+	if l.playing != nil {
+		l.playing.Process.Kill()
+	}
+	l.playing = nil
+}
+
+// player returns the configured player command, or the first one found on the PATH
+func (l *AudioLibrary) player() ([]string, error) {
+	if len(l.Player) > 0 {
+		return l.Player, nil
+	}
+	for _, player := range audioPlayers {
+		if _, err := exec.LookPath(player[0]); err == nil {
+			return player, nil
+		}
+	}
+	return nil, errors.New("no audio player found, set audio_player in the config")
+}
+
+// PlayCurrentCard plays the local pronunciation audio for the current card
+func (a *App) PlayCurrentCard() {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+
+	files, err := a.Audio.Resolve(card)
+	if err != nil {
+		// There is no text-to-speech fallback yet, so just report it
+		a.SetStatus(err.Error())
+		return
+	}
+
+	go func() {
+		defer a.recoverPanic()
+		if err := a.Audio.Play(files); err != nil {
+			a.Application.QueueUpdateDraw(func() {
+				a.SetStatus(a.errorStatus("Error playing audio", err))
+			})
+		}
+	}()
+}
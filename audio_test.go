@@ -0,0 +1,34 @@
+// audio_test.go
+package main
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestAudioStop(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("no sleep command to stand in for a player")
+	}
+	// The "files" are durations for sleep, which stands in for the player
+	l := NewAudioLibrary("", []string{"sleep"})
+
+	done := make(chan error)
+	go func() {
+		done <- l.Play([]string{"10", "10"})
+	}()
+	// Stopped right away, possibly before the player started, and again once it has
+	l.Stop()
+	time.Sleep(100 * time.Millisecond)
+	l.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Play: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Play still running after Stop")
+	}
+}
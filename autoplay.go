@@ -0,0 +1,110 @@
+// autoplay.go
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default auto-play delays in seconds, used when the config leaves them unset
+const (
+	defaultAutoplayReveal  = 5
+	defaultAutoplayAdvance = 5
+)
+
+// autoplay is a running auto-play session. Its fields are only accessed from
+// the UI goroutine, the ticker goroutine queues every tick as a UI update.
+type autoplay struct {
+	ticker  *time.Ticker
+	done    chan struct{}
+	paused  bool
+	elapsed int
+}
+
+// ToggleAutoplay starts or stops revealing and advancing cards on a timer
+func (a *App) ToggleAutoplay() {
+	if a.autoplay != nil {
+		a.StopAutoplay()
+		a.SetStatus("Auto-play off")
+		return
+	}
+
+	p := &autoplay{
+		ticker: time.NewTicker(time.Second),
+		done:   make(chan struct{}),
+	}
+	a.autoplay = p
+	go func() {
+		defer a.recoverPanic()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-p.ticker.C:
+				a.Application.QueueUpdateDraw(func() {
+					a.autoplayTick(p)
+				})
+			}
+		}
+	}()
+
+	a.SetStatus(fmt.Sprintf("Auto-play on: reveal after %ds, next card after %ds. Any key pauses, a stops",
+		a.autoplayReveal(), a.autoplayAdvance()))
+}
+
+// StopAutoplay stops the auto-play ticker, if running
+func (a *App) StopAutoplay() {
+	if a.autoplay == nil {
+		return
+	}
+	a.autoplay.ticker.Stop()
+	close(a.autoplay.done)
+	a.autoplay = nil
+}
+
+// PauseAutoplay pauses or resumes auto-play
+func (a *App) PauseAutoplay() {
+	if a.autoplay == nil {
+		return
+	}
+	a.autoplay.paused = !a.autoplay.paused
+	if a.autoplay.paused {
+		a.SetStatus("Auto-play paused, press any key to resume")
+	} else {
+		a.SetStatus("")
+	}
+}
+
+// autoplayTick counts a second of auto-play and reveals or advances when its delay has passed
+func (a *App) autoplayTick(p *autoplay) {
+	// A tick queued before the session was stopped or replaced is ignored
+	if a.autoplay != p || p.paused || a.Application.GetFocus() != a.CardView {
+		return
+	}
+
+	p.elapsed++
+	delay := a.autoplayReveal()
+	if a.RevealStep >= a.RevealStyle.Steps() {
+		delay = a.autoplayAdvance()
+	}
+	if p.elapsed >= delay {
+		p.elapsed = 0
+		a.Advance()
+	}
+}
+
+// autoplayReveal returns the configured delay before each reveal step
+func (a *App) autoplayReveal() int {
+	if a.Config.AutoplayReveal > 0 {
+		return a.Config.AutoplayReveal
+	}
+	return defaultAutoplayReveal
+}
+
+// autoplayAdvance returns the configured delay between the full reveal and the next card
+func (a *App) autoplayAdvance() int {
+	if a.Config.AutoplayAdvance > 0 {
+		return a.Config.AutoplayAdvance
+	}
+	return defaultAutoplayAdvance
+}
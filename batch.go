@@ -0,0 +1,87 @@
+// batch.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// SaveNewCards translates and adds several cards in the background, showing progress.
+// Each card is saved as soon as it is translated, and English already in the deck is
+// skipped, so adding the same lines again after an interruption picks up where it stopped.
+func (a *App) SaveNewCards(englishTexts []string, source string) {
+	progress := a.showProgress(" Adding Cards ", "")
+
+	go func() {
+		defer a.recoverPanic()
+		var added, skipped int
+		var failures, warnings []string
+		for i, text := range englishTexts {
+			if a.findEnglish(text) >= 0 {
+				a.Logger.Info("skipped card already in the deck", "english", text)
+				skipped++
+				continue
+			}
+			status := fmt.Sprintf("\nTranslating %d/%d\n\n%s", i+1, len(englishTexts), text)
+			if a.AI.Limiter != nil {
+				status += fmt.Sprintf("\n\n(limited to %d requests per minute)", a.AI.Limiter.PerMinute)
+			}
+			a.Application.QueueUpdateDraw(func() {
+				progress.SetText(status)
+			})
+
+			card, err := a.AddCard(text, source)
+			if err != nil {
+				a.Logger.Error("Error adding card", "english", text, "error", err)
+				failures = append(failures, fmt.Sprintf("%q: %v", text, err))
+				continue
+			}
+			if warning := a.latinWarning(card); warning != "" {
+				warnings = append(warnings, warning)
+			}
+			added++
+		}
+
+		a.Application.QueueUpdateDraw(func() {
+			message := fmt.Sprintf("Added %d cards", added)
+			if skipped > 0 {
+				message += fmt.Sprintf(", skipped %d already in the deck", skipped)
+			}
+			if len(failures) > 0 {
+				message += fmt.Sprintf(", %d failed: %s", len(failures), strings.Join(failures, "; "))
+			}
+			if len(warnings) > 0 {
+				message += ". " + strings.Join(warnings, ". ")
+			}
+			a.Application.SetRoot(a.MainView, true)
+			a.SetStatus(message)
+		})
+	}()
+}
+
+// showProgress displays a dialog with the given title and text, returning the text view
+// for background work to report its progress in with QueueUpdateDraw
+func (a *App) showProgress(title, text string) *tview.TextView {
+	progress := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetWordWrap(true).
+		SetText(text)
+	progress.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignCenter)
+	a.Application.SetRoot(centered(progress), true)
+	return progress
+}
+
+// splitLines returns the non-empty lines of text, trimmed of surrounding whitespace
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
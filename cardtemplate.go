@@ -0,0 +1,75 @@
+// cardtemplate.go
+package main
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// cardTemplates replace the card view layout with the user's own, written with text/template
+// placeholders for the card's fields such as {{.English}} and {{.Pinyin}}. The front is shown
+// until the answer is fully revealed and the back after. A nil side uses the default layout.
+type cardTemplates struct {
+	front, back *template.Template
+}
+
+// parseCardTemplates parses the front and back templates from the config, returning nil if
+// neither is set. Each template is tried on an empty card, so placeholders naming unknown
+// fields are reported now rather than when a card is shown.
+func parseCardTemplates(front, back string) (*cardTemplates, error) {
+	if front == "" && back == "" {
+		return nil, nil
+	}
+	var templates cardTemplates
+	var err error
+	if templates.front, err = parseCardTemplate("front_template", front); err != nil {
+		return nil, err
+	}
+	if templates.back, err = parseCardTemplate("back_template", back); err != nil {
+		return nil, err
+	}
+	return &templates, nil
+}
+
+// parseCardTemplate parses and checks one side's template, nil if it is empty
+func parseCardTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	// Errors name the template, so they say which side is wrong
+	if err := t.Execute(io.Discard, chinese.Flashcard{}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// templateBody renders the card with the template of the side being shown. It returns false
+// if the default layout should be used instead: when there is no template for the side,
+// the template fails, or in reverse and masked privacy modes, which templates know nothing of.
+func (a *App) templateBody(card chinese.Flashcard) (string, bool) {
+	if a.templates == nil || a.Reverse || a.Privacy && !a.Unmasked {
+		return "", false
+	}
+	t := a.templates.front
+	if a.RevealStep == a.RevealStyle.Steps() {
+		t = a.templates.back
+	}
+	if t == nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, card); err != nil {
+		return "", false
+	}
+	return tview.Escape(strings.TrimRight(b.String(), "\n")) + "\n", true
+}
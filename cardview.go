@@ -0,0 +1,133 @@
+// cardview.go
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// DefaultCardView lists the fields of the card view in their default order
+var DefaultCardView = []string{"english", "mnemonic", "chinese", "pinyin", "measure_word", "notes", "source", "tags"}
+
+// ValidateCardView checks a card view layout only names known fields, each at most once
+func ValidateCardView(fields []string) error {
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if !slices.Contains(DefaultCardView, field) {
+			return fmt.Errorf("unknown card view field %q, expected one of %s", field, strings.Join(DefaultCardView, ", "))
+		}
+		if seen[field] {
+			return fmt.Errorf("card view field %q is listed twice", field)
+		}
+		seen[field] = true
+	}
+	return nil
+}
+
+// cardSection renders one field of the card view, or returns "" if the card has
+// nothing to show for it at the current reveal step
+func (a *App) cardSection(field string, card chinese.Flashcard) string {
+	theme := a.Theme
+	showChinese, showPinyin := a.RevealStyle.Shows(a.RevealStep)
+	// In reverse mode the English is the answer, revealed when the Chinese would be
+	showEnglish := true
+	if a.Reverse {
+		showEnglish, showChinese = showChinese, true
+	}
+
+	var section strings.Builder
+	switch field {
+	case "english":
+		if !showEnglish {
+			break
+		}
+		section.WriteString(theme.LabelTag("English:") + "\n")
+		cards := []chinese.Flashcard{card}
+		if a.mergesHomographs() {
+			cards = a.homographsOf(card)
+		}
+		for _, c := range cards {
+			english := c.English
+			if a.Reverse {
+				english = a.answer(english)
+			}
+			section.WriteString(theme.Color(theme.English, tview.Escape(english)) + "\n")
+			if c.Gloss != "" {
+				section.WriteString("(" + tview.Escape(c.Gloss) + ")\n")
+			}
+		}
+	case "mnemonic":
+		// The mnemonic is a cue for the English
+		if !showEnglish || card.Mnemonic == "" && card.Image == "" {
+			break
+		}
+		section.WriteString(theme.LabelTag("Mnemonic:") + "\n")
+		if card.Mnemonic != "" {
+			section.WriteString(tview.Escape(card.Mnemonic) + "\n")
+		}
+		if card.Image != "" {
+			section.WriteString("Image: " + tview.Escape(card.Image) + "\n")
+		}
+	case "chinese":
+		if showChinese {
+			section.WriteString(theme.LabelTag("Chinese:") + "\n")
+			section.WriteString(a.chineseText(card.Chinese) + "\n")
+			if a.ShowStrokes {
+				if strokes := strokeSummary(card.Chinese); strokes != "" {
+					if a.hidesChinese() {
+						strokes = mask(strokes)
+					}
+					section.WriteString("[::d]Strokes: " + tview.Escape(strokes) + "[::-]\n")
+				}
+			}
+		}
+	case "pinyin":
+		if !showPinyin {
+			break
+		}
+		if a.ShowSpoken && card.SpokenPinyin != "" {
+			section.WriteString(theme.LabelTag("Spoken Pinyin:") + "\n")
+			section.WriteString(a.pinyinText(card.SpokenPinyin) + "\n")
+		} else {
+			section.WriteString(theme.LabelTag("Pinyin:") + "\n")
+			section.WriteString(a.pinyinText(card.Pinyin) + "\n")
+		}
+		if card.SpokenPinyin != "" && !a.ShowSpoken {
+			section.WriteString("(t: spoken pinyin)\n")
+		}
+	case "measure_word":
+		// The measure word is part of the Chinese answer
+		if showChinese && card.Classifier != "" {
+			classifier := card.Classifier
+			if a.hidesChinese() {
+				classifier = mask(classifier)
+			}
+			section.WriteString(theme.LabelTag("Measure word:") + "\n")
+			section.WriteString(theme.Color(theme.Chinese, tview.Escape(classifier)) + "\n")
+		}
+	case "source":
+		if card.Source != "" {
+			section.WriteString("[::d]Source: " + tview.Escape(card.Source) + "[::-]\n")
+		}
+	case "tags":
+		if len(card.Tags) > 0 {
+			section.WriteString("[::d]Tags: " + tview.Escape(strings.Join(card.Tags, ", ")) + "[::-]\n")
+		}
+	case "notes":
+		if card.Notes == "" {
+			break
+		}
+		if a.ShowNotes {
+			section.WriteString(theme.LabelTag("Notes:") + "\n")
+			section.WriteString(tview.Escape(card.Notes) + "\n")
+		} else {
+			section.WriteString("(o: show notes)\n")
+		}
+	}
+	return section.String()
+}
@@ -0,0 +1,24 @@
+// check.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// checkTimeout is how long --check waits for the API to answer
+const checkTimeout = 15 * time.Second
+
+// CheckConnection verifies the API key works and the model is available, and reports
+// the result to out
+func (a *App) CheckConnection(out io.Writer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	if err := a.AI.CheckConnection(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "OK: the API key works and model %s is available\n", a.AI.Model)
+	return nil
+}
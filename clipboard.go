@@ -0,0 +1,119 @@
+// clipboard.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// clipboardCopyCommands are the commands tried, in order, to write to the system clipboard
+var clipboardCopyCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// clipboardPasteCommands are the commands tried, in order, to read the system clipboard
+var clipboardPasteCommands = [][]string{
+	{"pbpaste"},
+	{"wl-paste", "--no-newline"},
+	{"xclip", "-selection", "clipboard", "-out"},
+	{"xsel", "--clipboard", "--output"},
+	{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard"},
+}
+
+// ErrNoClipboard is returned when no clipboard tool is available, e.g. over SSH
+var ErrNoClipboard = errors.New("no clipboard available")
+
+// copyToClipboard writes text to the system clipboard using the first available tool
+func copyToClipboard(text string) error {
+	for _, command := range clipboardCopyCommands {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return ErrNoClipboard
+}
+
+// readClipboard returns the text of the system clipboard using the first available tool
+func readClipboard() (string, error) {
+	for _, command := range clipboardPasteCommands {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(command[0], command[1:]...).Output()
+		return string(out), err
+	}
+	return "", ErrNoClipboard
+}
+
+// clipboardPreviewLines is how many lines of the clipboard the quick add preview shows
+const clipboardPreviewLines = 5
+
+// QuickAddFromClipboard adds the English text in the clipboard as new cards, one per
+// line, after showing it for confirmation, without going through the new card dialog
+func (a *App) QuickAddFromClipboard() {
+	text, err := readClipboard()
+	if err != nil {
+		a.SetStatus("Could not read the clipboard: " + err.Error() + ". Press n to type or paste the English instead")
+		return
+	}
+	lines := splitLines(text)
+	if len(lines) == 0 {
+		a.SetStatus("The clipboard has no text to add")
+		return
+	}
+
+	preview := lines[:min(len(lines), clipboardPreviewLines)]
+	message := "Add this card from the clipboard?\n\n" + strings.Join(preview, "\n")
+	if len(lines) > 1 {
+		message = fmt.Sprintf("Add %d cards from the clipboard?\n\n%s", len(lines), strings.Join(preview, "\n"))
+	}
+	if len(lines) > len(preview) {
+		message += fmt.Sprintf("\n(and %d more)", len(lines)-len(preview))
+	}
+
+	modal := tview.NewModal().
+		SetText(tview.Escape(message)).
+		AddButtons([]string{"Add", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			if label != "Add" {
+				a.Application.SetRoot(a.MainView, true)
+				return
+			}
+			if len(lines) == 1 {
+				a.SaveNewCard(lines[0], "")
+			} else {
+				a.SaveNewCards(lines, "")
+			}
+		})
+	a.Application.SetRoot(modal, true)
+}
+
+// CopyCurrentCard copies the current card's Chinese, or the whole card, to the clipboard
+func (a *App) CopyCurrentCard(full bool) {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+
+	text, what := card.Chinese, "Chinese"
+	if full {
+		text, what = card.English+"\n"+card.Chinese+"\n"+card.Pinyin, "card"
+	}
+
+	if err := copyToClipboard(text); err != nil {
+		a.SetStatus("Could not copy to clipboard: " + err.Error())
+		return
+	}
+	a.SetStatus("Copied " + what + " to clipboard")
+}
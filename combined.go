@@ -0,0 +1,110 @@
+// combined.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"chinese/pkg/chinese"
+)
+
+// ErrCombinedDeck is returned when saving changes other than new cards to a deck loaded from several files
+var ErrCombinedDeck = errors.New("a deck combined from several files is read-only, except that new cards can be added")
+
+// LoadDecks loads the cards of several files into one combined deck, remembering the
+// file each card came from. Cards whose ID is already taken by an earlier file get a new
+// ID for this session, above every ID in the files. The combined deck can't be rewritten,
+// but new cards are appended to the file of the card being reviewed when they are added.
+// The state file keeps no statistics for renumbered cards, as their ID is only theirs
+// for the session.
+func (a *App) LoadDecks(filenames []string) error {
+	files := make([][]chinese.Flashcard, len(filenames))
+	maxID := 0
+	for i, filename := range filenames {
+		if isURL(filename) {
+			return fmt.Errorf("%s: only local files can be combined", filename)
+		}
+		cards, err := readCards(filename, a.Config.FieldNames)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		for _, card := range cards {
+			maxID = max(maxID, card.ID)
+		}
+		files[i] = cards
+	}
+
+	a.DeckFiles = filenames
+	a.cardFiles = make(map[int]string)
+	a.renumbered = make(map[int]bool)
+	for i, cards := range files {
+		for _, card := range cards {
+			if _, taken := a.cardFiles[card.ID]; taken {
+				maxID++
+				card.ID = maxID
+				a.renumbered[card.ID] = true
+			}
+			a.cardFiles[card.ID] = filenames[i]
+			a.Deck = append(a.Deck, card)
+		}
+	}
+	return nil
+}
+
+// cardFile returns the file a card of a combined deck came from, or the first file
+// for a card from none of them. The caller must hold a.mu.
+func (a *App) cardFile(id int) string {
+	if filename, ok := a.cardFiles[id]; ok {
+		return filename
+	}
+	return a.DeckFiles[0]
+}
+
+// combinedTarget returns the file new cards of a combined deck are appended to, the file
+// of the card being reviewed. The caller must hold a.mu.
+func (a *App) combinedTarget() string {
+	if a.CurrentCardIdx < len(a.Deck) {
+		return a.cardFile(a.Deck[a.CurrentCardIdx].ID)
+	}
+	return a.DeckFiles[0]
+}
+
+// appendToCombinedDeck appends a new card to the file of the card being reviewed.
+// The caller must hold a.mu.
+func (a *App) appendToCombinedDeck(card chinese.Flashcard) error {
+	filename := a.combinedTarget()
+	if isGzip(filename) {
+		return fmt.Errorf("can't add cards to %s: %w", filename, ErrCombinedDeck)
+	}
+	if err := appendCardLine(filename, card, a.Durable); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	a.cardFiles[card.ID] = filename
+	return nil
+}
+
+// keepsStats reports whether statistics are kept for the card with the given ID in the
+// state file. Cards of a combined deck given a new ID for the session have none: their ID
+// may belong to another card in a later session.
+func (a *App) keepsStats(id int) bool {
+	return !a.renumbered[id]
+}
+
+// cardStats returns the statistics of a card from the state file, nil if it has none
+func (a *App) cardStats(id int) *CardStats {
+	if !a.keepsStats(id) {
+		return nil
+	}
+	return a.State.Cards[id]
+}
+
+// combinedDeckName returns the names of the files of a combined deck
+func (a *App) combinedDeckName() string {
+	names := make([]string, len(a.DeckFiles))
+	for i, filename := range a.DeckFiles {
+		names[i] = filepath.Base(filename)
+	}
+	return strings.Join(names, " + ")
+}
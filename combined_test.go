@@ -0,0 +1,42 @@
+// combined_test.go
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCombinedDeckRenumberedStats(t *testing.T) {
+	dir := t.TempDir()
+	food, travel := filepath.Join(dir, "food.jsonl"), filepath.Join(dir, "travel.jsonl")
+	if err := os.WriteFile(food, []byte(`{"id":1,"en":"rice","zh":"米饭","pinyin":"mǐfàn"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(travel, []byte(`{"id":1,"en":"train","zh":"火车","pinyin":"huǒchē"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewApp("test-key", "test-model")
+	a.StateFile = filepath.Join(dir, "state.json")
+	if err := a.LoadDecks([]string{food, travel}); err != nil {
+		t.Fatalf("LoadDecks: %v", err)
+	}
+	renumbered := a.Deck[1].ID
+	if renumbered == 1 {
+		t.Fatalf("the second card kept the taken ID 1")
+	}
+
+	a.RecordReview(1)
+	a.RecordReview(renumbered)
+	a.RecordAnswer(renumbered, true)
+	if a.State.Cards[renumbered] != nil {
+		t.Errorf("stats were recorded for renumbered card %d: %+v", renumbered, a.State.Cards[renumbered])
+	}
+	if a.State.Cards[1] == nil || a.State.Cards[1].LastReviewed == nil {
+		t.Fatal("the review of card 1 wasn't recorded")
+	}
+	if reviews := a.State.ReviewsOn(*a.State.Cards[1].LastReviewed); reviews != 2 {
+		t.Errorf("%d reviews counted today, want both", reviews)
+	}
+}
@@ -0,0 +1,79 @@
+// confidence.go
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxConfidence is the highest confidence rating, for a card known perfectly
+const maxConfidence = 5
+
+// Rating is how confident the learner felt about a card when reviewing it, from 1 to 5
+type Rating struct {
+	Confidence int       `json:"confidence"`
+	At         time.Time `json:"at"`
+}
+
+// Confidence returns the latest confidence rating of the card, or 0 if it was never rated
+func (c CardStats) Confidence() int {
+	if len(c.Ratings) == 0 {
+		return 0
+	}
+	return c.Ratings[len(c.Ratings)-1].Confidence
+}
+
+// RecordConfidence adds a confidence rating for a card given at time t to its history
+func (s *State) RecordConfidence(id, confidence int, t time.Time) {
+	stats := s.cardStats(id)
+	stats.Ratings = append(stats.Ratings, Rating{Confidence: confidence, At: t})
+}
+
+// AverageConfidence returns the number of rated cards and the average of their latest ratings
+func (s *State) AverageConfidence() (rated int, average float64) {
+	var sum int
+	for _, stats := range s.Cards {
+		if confidence := stats.Confidence(); confidence > 0 {
+			rated++
+			sum += confidence
+		}
+	}
+	if rated == 0 {
+		return 0, 0
+	}
+	return rated, float64(sum) / float64(rated)
+}
+
+// RateConfidence records how confident the learner is about the current card, once its
+// answer is revealed. The rating doesn't change the review order unless weak card focus is on.
+func (a *App) RateConfidence(confidence int) {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+	if a.RevealStep < a.RevealStyle.Steps() {
+		a.SetStatus("Reveal the answer before rating your confidence")
+		return
+	}
+	if !a.keepsStats(card.ID) {
+		a.SetStatus(fmt.Sprintf("Confidence isn't recorded for card %d, its ID is only for this session", card.ID))
+		return
+	}
+	a.State.RecordConfidence(card.ID, confidence, time.Now())
+	if err := a.State.Save(a.StateFile); err != nil {
+		a.SetStatus(a.errorStatus("Error saving state", err))
+		return
+	}
+	a.SetStatus(fmt.Sprintf("Confidence %d/%d recorded", confidence, maxConfidence))
+}
+
+// confidenceSummary formats the average confidence for the statistics
+func confidenceSummary(rated int, average float64) string {
+	if rated == 0 {
+		return "none yet, press 1-5 after revealing a card"
+	}
+	if rated == 1 {
+		return fmt.Sprintf("%.0f/%d for the one rated card", average, maxConfidence)
+	}
+	return fmt.Sprintf("%.1f/%d average over %d cards", average, maxConfidence, rated)
+}
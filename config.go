@@ -0,0 +1,201 @@
+// config.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"chinese/pkg/chinese"
+)
+
+// Config holds user preferences loaded from a JSON file
+type Config struct {
+	Theme  string `json:"theme,omitempty"`
+	Colors *Theme `json:"colors,omitempty"`
+	Reveal string `json:"reveal,omitempty"`
+
+	// ToneColors names the palette coloring Pinyin by tone, empty for a single color
+	ToneColors string `json:"tone_colors,omitempty"`
+
+	HideControls  bool `json:"hide_controls,omitempty"`
+	HideStatusBar bool `json:"hide_status_bar,omitempty"`
+
+	// RequestsPerMinute caps the rate of translation requests, 0 means unlimited
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+
+	// Organization and Project select the OpenAI organization and project billed for requests
+	Organization string `json:"organization,omitempty"`
+	Project      string `json:"project,omitempty"`
+
+	// KnownModels replaces the list of model names accepted without a warning
+	KnownModels []string `json:"known_models,omitempty"`
+
+	// SegmentWords underlines every other word of the Chinese to show word boundaries.
+	// WordFile adds words to the bundled word list used to find them.
+	SegmentWords bool   `json:"segment_words,omitempty"`
+	WordFile     string `json:"word_file,omitempty"`
+
+	// DecompositionFile extends the bundled character decomposition table
+	DecompositionFile string `json:"decomposition_file,omitempty"`
+
+	// AutoplayReveal and AutoplayAdvance are the auto-play delays in seconds
+	// before revealing the answer and before moving on to the next card
+	AutoplayReveal  int `json:"autoplay_reveal_seconds,omitempty"`
+	AutoplayAdvance int `json:"autoplay_advance_seconds,omitempty"`
+
+	// Examples are extra sample translations sent with every translation request
+	Examples []chinese.Example `json:"examples,omitempty"`
+
+	// Sandhi requests the Pinyin as spoken after tone sandhi for new cards
+	Sandhi bool `json:"sandhi,omitempty"`
+
+	// LatinInChinese is what to do with translations whose Chinese contains Latin words:
+	// allow (default), retry or warn. AllowedLatin lists words that are always fine.
+	LatinInChinese string   `json:"latin_in_chinese,omitempty"`
+	AllowedLatin   []string `json:"allowed_latin,omitempty"`
+
+	// KeepHistory keeps the previous translations of cards when they are edited or re-translated
+	KeepHistory bool `json:"keep_history,omitempty"`
+
+	// NewCards is where new cards are queued for review: end (default), next or soon
+	NewCards string `json:"new_cards,omitempty"`
+
+	// RecentCards is how many cards the recently added filter keeps, 20 if unset
+	RecentCards int `json:"recent_cards,omitempty"`
+
+	// SessionMinutes is how long a session lasts before a break is suggested, 0 for no limit
+	SessionMinutes int `json:"session_minutes,omitempty"`
+
+	// WeakAccuracyWeight, WeakRecentWeight and WeakRecentDays tune how much more often the
+	// weak card focus mode shows cards with a low quiz accuracy and cards recently answered wrongly
+	WeakAccuracyWeight float64 `json:"weak_accuracy_weight,omitempty"`
+	WeakRecentWeight   float64 `json:"weak_recent_weight,omitempty"`
+	WeakRecentDays     int     `json:"weak_recent_days,omitempty"`
+
+	// WeakConfidenceWeight is how much more often weak card focus shows cards rated with a low confidence
+	WeakConfidenceWeight float64 `json:"weak_confidence_weight,omitempty"`
+
+	// StaleDecayDays is how many days without a review add 1 to a card's weight in stale card focus
+	StaleDecayDays float64 `json:"stale_decay_days,omitempty"`
+
+	// Homographs is how reverse mode shows cards with the same Chinese: merge (default) or separate
+	Homographs string `json:"homographs,omitempty"`
+
+	// CardView lists the fields shown on the card view, in order
+	CardView []string `json:"card_view,omitempty"`
+
+	// FrontTemplate and BackTemplate replace the card view layout before and after the
+	// answer is revealed, using text/template placeholders such as {{.English}}
+	FrontTemplate string `json:"front_template,omitempty"`
+	BackTemplate  string `json:"back_template,omitempty"`
+
+	// FieldNames lets decks use their own names for card fields
+	FieldNames FieldNames `json:"field_names,omitempty"`
+
+	AudioDir    string   `json:"audio_dir,omitempty"`
+	AudioPlayer []string `json:"audio_player,omitempty"`
+}
+
+// LoadConfig reads the configuration file, returning defaults if it does not exist
+func LoadConfig(filename string) (*Config, error) {
+	config := &Config{}
+	if filename == "" {
+		return config, nil
+	}
+
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", filename, err)
+	}
+	switch config.NewCards {
+	case "", NewCardsEnd, NewCardsNext, NewCardsSoon:
+	default:
+		return nil, fmt.Errorf("invalid config file %s: new_cards must be %s, %s or %s, not %q",
+			filename, NewCardsEnd, NewCardsNext, NewCardsSoon, config.NewCards)
+	}
+	if _, ok := TonePalettes[config.ToneColors]; config.ToneColors != "" && !ok {
+		return nil, fmt.Errorf("invalid config file %s: tone_colors must be one of %s, not %q",
+			filename, strings.Join(slices.Sorted(maps.Keys(TonePalettes)), ", "), config.ToneColors)
+	}
+	if config.WeakAccuracyWeight < 0 || config.WeakRecentWeight < 0 || config.WeakRecentDays < 0 || config.WeakConfidenceWeight < 0 {
+		return nil, fmt.Errorf("invalid config file %s: weak card weights and days can't be negative", filename)
+	}
+	if config.StaleDecayDays < 0 {
+		return nil, fmt.Errorf("invalid config file %s: stale_decay_days can't be negative", filename)
+	}
+	switch config.Homographs {
+	case "", HomographsMerge, HomographsSeparate:
+	default:
+		return nil, fmt.Errorf("invalid config file %s: homographs must be %s or %s, not %q",
+			filename, HomographsMerge, HomographsSeparate, config.Homographs)
+	}
+	switch config.LatinInChinese {
+	case "", LatinAllow, LatinRetry, LatinWarn:
+	default:
+		return nil, fmt.Errorf("invalid config file %s: latin_in_chinese must be %s, %s or %s, not %q",
+			filename, LatinAllow, LatinRetry, LatinWarn, config.LatinInChinese)
+	}
+	if err := chinese.ValidateExamples(config.Examples); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", filename, err)
+	}
+	if err := ValidateCardView(config.CardView); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", filename, err)
+	}
+	if _, err := parseCardTemplates(config.FrontTemplate, config.BackTemplate); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", filename, err)
+	}
+	if err := config.FieldNames.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", filename, err)
+	}
+	return config, nil
+}
+
+// SaveConfig writes the configuration to a file
+func SaveConfig(filename string, config *Config) error {
+	b, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(b, '\n'), 0644)
+}
+
+// CardViewFields returns the fields shown on the card view, in order
+func (c *Config) CardViewFields() []string {
+	if c.CardView == nil {
+		return DefaultCardView
+	}
+	return c.CardView
+}
+
+// ResolveTheme returns the theme with the given name, or the configured one if name is empty
+func (c *Config) ResolveTheme(name string) (Theme, error) {
+	if name == "" {
+		name = c.Theme
+	}
+	if name == "" {
+		name = DefaultThemeName
+	}
+
+	theme, ok := Themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q", name)
+	}
+
+	// Custom colors override individual entries of the selected preset
+	if c.Colors != nil {
+		theme = theme.Merge(*c.Colors)
+	}
+	return theme, nil
+}
@@ -0,0 +1,24 @@
+// crash.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+)
+
+// recoverPanic is deferred in the main goroutine and in every background goroutine of the
+// UI. An unrecovered panic outside the UI goroutine would exit with the terminal still in
+// raw mode, and one inside it would skip saving the deck. Instead the terminal is restored,
+// unsaved changes are written if possible, and the panic is printed with its stack trace.
+func (a *App) recoverPanic() {
+	p := recover()
+	if p == nil {
+		return
+	}
+	// Stop restores the terminal, and does nothing if tview already has
+	a.Application.Stop()
+	fmt.Fprintf(os.Stderr, "The program crashed: %v\n\n%s\n", p, debug.Stack())
+	a.saveUnsaved(os.Stderr)
+	os.Exit(2)
+}
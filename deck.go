@@ -0,0 +1,291 @@
+// deck.go
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"chinese/pkg/chinese"
+)
+
+// LoadDeck loads flashcards from a JSONL file, optionally gzip-compressed.
+// A missing file is an empty deck; it is created when the first card is added.
+func (a *App) LoadDeck(filename string) error {
+	a.FlashcardsFile = filename
+	cards, err := readCards(filename, a.Config.FieldNames)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	a.Deck = append(a.Deck, cards...)
+	a.noteWritten(cards)
+	return nil
+}
+
+// readCards reads all cards from a JSONL file or http(s) URL, optionally gzip-compressed
+func readCards(filename string, names FieldNames) ([]chinese.Flashcard, error) {
+	if isURL(filename) {
+		return fetchCards(filename, names)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decodeCards(file, isGzip(filename), names)
+}
+
+// Positions of new cards in the flashcards file
+const (
+	NewCardAppend  = "append"
+	NewCardPrepend = "prepend"
+)
+
+// Byte order marks at the start of text files
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// decodeCards reads all cards from JSONL, optionally gzip-compressed.
+// Custom field names are renamed and cards from older versions of the file format
+// are migrated to the current one.
+func decodeCards(r io.Reader, compressed bool, names FieldNames) ([]chinese.Flashcard, error) {
+	if compressed {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	// Large decks load much faster decoding each line in place than through a
+	// json.Decoder, which copies every value, with the slice sized up front
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, utf16LEBOM) || bytes.HasPrefix(data, utf16BEBOM) {
+		return nil, errors.New("the file is encoded as UTF-16, save it as UTF-8 instead")
+	}
+	// Files saved by Windows tools often start with a UTF-8 byte order mark
+	data = bytes.TrimPrefix(data, utf8BOM)
+	cards := make([]chinese.Flashcard, 0, bytes.Count(data, []byte{'\n'})+1)
+	version := 1
+	for first, lineNumber := true, 1; len(data) > 0; lineNumber++ {
+		var line []byte
+		line, data, _ = bytes.Cut(data, []byte{'\n'})
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		// JSON decoding would silently replace the invalid bytes, losing the text
+		if !utf8.Valid(line) {
+			return nil, fmt.Errorf("line %d: not valid UTF-8, the file may use another encoding such as GBK; save it as UTF-8", lineNumber)
+		}
+		if first {
+			first = false
+			v, ok, err := parseDeckHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				version = v
+				continue
+			}
+		}
+		card, err := decodeCard(line, version, names)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// saveCards writes the cards to a new JSONL file, replacing any existing file
+func saveCards(filename string, cards []chinese.Flashcard) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = writeCards(file, cards, isGzip(filename))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// appendCard appends a single card to the flashcards file.
+// Gzip streams can't be appended to in place, so compressed decks are rewritten in full,
+// as is the local copy of a deck loaded from a URL until it exists.
+// The caller must hold a.mu.
+func (a *App) appendCard(card chinese.Flashcard) error {
+	if a.DeckFiles != nil {
+		err := a.appendToCombinedDeck(card)
+		if err != nil {
+			a.dirty = true
+		}
+		return err
+	}
+	if isGzip(a.FlashcardsFile) {
+		return a.rewriteDeck()
+	}
+	// The first change to a deck loaded from a URL writes the whole downloaded deck
+	if _, err := os.Stat(a.FlashcardsFile); a.DeckURL != "" && errors.Is(err, os.ErrNotExist) {
+		return a.rewriteDeck()
+	}
+
+	if err := appendCardLine(a.FlashcardsFile, card, a.Durable); err != nil {
+		// The card is in memory but not on disk until the next full rewrite
+		a.dirty = true
+		return err
+	}
+	a.noteWritten(append(a.savedDeck, card))
+	return nil
+}
+
+// appendCardLine writes the card as a new line at the end of a flashcards file,
+// waiting until it is on disk if durable is set
+func appendCardLine(filename string, card chinese.Flashcard, durable bool) error {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening flashcards file: %w", err)
+	}
+	defer file.Close()
+
+	cardJSON, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("marshaling new card: %w", err)
+	}
+	line := append(cardJSON, '\n')
+	// A new file starts with the header; existing files keep theirs, or none for version 1
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		line = append(headerLine(), line...)
+	}
+	if _, err := file.Write(line); err != nil {
+		return fmt.Errorf("writing new card to file: %w", err)
+	}
+	if durable {
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("syncing flashcards file: %w", err)
+		}
+	}
+	return nil
+}
+
+// rewriteDeck writes the whole deck to the flashcards file, replacing its contents.
+// The deck is marked dirty until the write succeeds. The caller must hold a.mu.
+func (a *App) rewriteDeck() error {
+	if err := a.checkWritable(); err != nil {
+		return err
+	}
+	a.dirty = true
+	tmp := a.FlashcardsFile + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("creating flashcards file: %w", err)
+	}
+
+	err = writeCards(file, a.Deck, isGzip(a.FlashcardsFile))
+	if err == nil && a.Durable {
+		// The new contents must be on disk before the rename replaces the old file
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing flashcards file: %w", err)
+	}
+	if err := os.Rename(tmp, a.FlashcardsFile); err != nil {
+		return err
+	}
+	a.noteWritten(slices.Clone(a.Deck))
+	a.dirty = false
+	return nil
+}
+
+// checkWritable returns why the deck can't be rewritten, or nil if it can. Changes are
+// checked before they are made, so a deck that can't be saved is never changed in memory
+// only. The caller must hold a.mu.
+func (a *App) checkWritable() error {
+	if a.DeckFiles != nil {
+		return ErrCombinedDeck
+	}
+	if a.FlashcardsFile == "" {
+		return ErrReadOnlyDeck
+	}
+	return nil
+}
+
+// checkCanAdd returns why a new card can't be added to the deck, or nil if it can.
+// Combined decks take new cards at the end of a plain JSONL file.
+// The caller must hold a.mu.
+func (a *App) checkCanAdd() error {
+	if a.DeckFiles != nil {
+		if filename := a.combinedTarget(); isGzip(filename) {
+			return fmt.Errorf("can't add cards to %s: %w", filename, ErrCombinedDeck)
+		}
+		return nil
+	}
+	return a.checkWritable()
+}
+
+// SaveDeck rewrites the whole deck to the flashcards file
+func (a *App) SaveDeck() error {
+	return a.mutateDeck(a.rewriteDeck)
+}
+
+// IsDirty reports whether the deck has changes that failed to be written to the file
+func (a *App) IsDirty() bool {
+	var dirty bool
+	a.readDeck(func() {
+		dirty = a.dirty
+	})
+	return dirty
+}
+
+// writeCards writes the header and the cards as JSONL, gzip-compressed if requested
+func writeCards(w io.Writer, cards []chinese.Flashcard, compress bool) error {
+	if compress {
+		gz := gzip.NewWriter(w)
+		if err := writeCards(gz, cards, false); err != nil {
+			return err
+		}
+		return gz.Close()
+	}
+
+	if _, err := w.Write(headerLine()); err != nil {
+		return err
+	}
+	for _, card := range cards {
+		cardJSON, err := json.Marshal(card)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(cardJSON, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isGzip reports whether the deck file is gzip-compressed, based on its extension
+func isGzip(filename string) bool {
+	return strings.HasSuffix(filename, ".gz")
+}
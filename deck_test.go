@@ -0,0 +1,103 @@
+// deck_test.go
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"chinese/pkg/chinese"
+)
+
+func TestDecodeCardsEncoding(t *testing.T) {
+	const header = `{"_meta":{"version":2}}` + "\n"
+	const card = `{"id":1,"en":"Hello","zh":"你好","pinyin":"Nǐ hǎo"}` + "\n"
+	tests := []struct {
+		name string
+		data string
+		// wantErr is part of the expected error, "" if the deck should load
+		wantErr string
+	}{
+		{"plain", header + card, ""},
+		{"UTF-8 BOM", "\xEF\xBB\xBF" + header + card, ""},
+		{"UTF-8 BOM without header", "\xEF\xBB\xBF" + card, ""},
+		{"UTF-16 little endian", "\xFF\xFE{\x00", "UTF-16"},
+		{"UTF-16 big endian", "\xFE\xFF\x00{", "UTF-16"},
+		{"GBK", header + `{"id":1,"en":"Hello","zh":"` + "\xC4\xE3\xBA\xC3" + `","pinyin":"Nǐ hǎo"}` + "\n", "line 2: not valid UTF-8"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cards, err := decodeCards(strings.NewReader(test.data), false, FieldNames{})
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("decodeCards error = %v, want one containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCards: %v", err)
+			}
+			if len(cards) != 1 || cards[0].ID != 1 || cards[0].Chinese != "你好" {
+				t.Errorf("decodeCards = %+v, want card 1 with Chinese 你好", cards)
+			}
+		})
+	}
+}
+
+func TestDecodeCardVersions(t *testing.T) {
+	want := chinese.Flashcard{ID: 1, English: "Hello", Chinese: "你好", Pinyin: "Nǐ hǎo", Tags: []string{"greetings"}}
+	tests := []struct {
+		name    string
+		line    string
+		version int
+		names   FieldNames
+	}{
+		{"version 1", `{"id":1,"en":"Hello","zh":"你好","pinyin":"Nǐ hǎo","tags":["greetings"]}`, 1, nil},
+		{"version 2", `{"id":1,"en":"Hello","zh":"你好","pinyin":"Nǐ hǎo","tags":["greetings"]}`, 2, nil},
+		{"unchanged names", `{"id":1,"en":"Hello","zh":"你好","pinyin":"Nǐ hǎo","tags":["greetings"]}`, 1, FieldNames{"en": "en"}},
+		{"custom names", `{"id":1,"english":"Hello","zh":"你好","pinyin":"Nǐ hǎo","tags":["greetings"]}`, 1, FieldNames{"en": "english"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			card, err := decodeCard([]byte(test.line), test.version, test.names)
+			if err != nil {
+				t.Fatalf("decodeCard: %v", err)
+			}
+			if !sameCard(card, want) {
+				t.Errorf("decodeCard = %+v, want %+v", card, want)
+			}
+		})
+	}
+}
+
+// BenchmarkLoadDeck measures loading a deck of a few thousand cards from a file
+func BenchmarkLoadDeck(b *testing.B) {
+	cards := make([]chinese.Flashcard, 5000)
+	for i := range cards {
+		cards[i] = chinese.Flashcard{
+			ID:      i + 1,
+			English: fmt.Sprintf("I'll probably have time next week, sentence number %d", i+1),
+			Chinese: "我下周可能有时间，可以吗？",
+			Pinyin:  "Wǒ xià zhōu kěnéng yǒu shíjiān, kěyǐ ma?",
+			Notes:   "可能 means probably",
+			Tags:    []string{"time", "HSK 2"},
+		}
+	}
+	filename := filepath.Join(b.TempDir(), "flashcards.jsonl")
+	if err := saveCards(filename, cards); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		a := &App{Config: &Config{}, Logger: discardLogger}
+		if err := a.LoadDeck(filename); err != nil {
+			b.Fatal(err)
+		}
+		if len(a.Deck) != len(cards) {
+			b.Fatalf("loaded %d cards, want %d", len(a.Deck), len(cards))
+		}
+	}
+}
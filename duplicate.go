@@ -0,0 +1,49 @@
+// duplicate.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+// findEnglish returns the deck index of a card with the same English text, ignoring
+// case and spacing, or -1 if there is none
+func (a *App) findEnglish(englishText string) int {
+	normalized := normalizeEnglish(englishText)
+	idx := -1
+	a.readDeck(func() {
+		for i, card := range a.Deck {
+			if normalizeEnglish(card.English) == normalized {
+				idx = i
+				return
+			}
+		}
+	})
+	return idx
+}
+
+// ShowDuplicateDialog tells the user the English is already the card at the given deck
+// index and lets them jump to it, add the new card anyway by calling add, or cancel
+func (a *App) ShowDuplicateDialog(englishText string, idx int, add func()) {
+	var id int
+	a.readDeck(func() {
+		id = a.Deck[idx].ID
+	})
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%q is already in the deck as card %d", englishText, id)).
+		AddButtons([]string{"Jump to it", "Add anyway", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			switch label {
+			case "Jump to it":
+				a.jumpTo(idx)
+			case "Add anyway":
+				add()
+				return
+			}
+			a.Application.SetRoot(a.MainView, true)
+			a.UpdateCardView()
+		})
+	a.Application.SetRoot(modal, true)
+}
@@ -0,0 +1,136 @@
+// edit.go
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// ShowEditCardDialog displays a form to edit the current card
+func (a *App) ShowEditCardDialog() {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+
+	form := tview.NewForm()
+	form.AddInputField("English", card.English, 50, nil, func(text string) {
+		card.English = text
+	})
+	form.AddInputField("Meaning", card.Gloss, 50, nil, func(text string) {
+		card.Gloss = text
+	})
+	form.AddInputField("Chinese", card.Chinese, 50, nil, func(text string) {
+		card.Chinese = text
+	})
+	form.AddInputField("Pinyin", card.Pinyin, 50, nil, func(text string) {
+		card.Pinyin = text
+	})
+	form.AddInputField("Spoken Pinyin", card.SpokenPinyin, 50, nil, func(text string) {
+		card.SpokenPinyin = text
+	})
+	form.AddInputField("Measure word", card.Classifier, 50, nil, func(text string) {
+		card.Classifier = text
+	})
+	form.AddInputField("HSK level", hskLevelText(card.HSKLevel), 50, tview.InputFieldInteger, func(text string) {
+		card.HSKLevel, _ = strconv.Atoi(text)
+	})
+	form.AddInputField("Mnemonic", card.Mnemonic, 50, nil, func(text string) {
+		card.Mnemonic = text
+	})
+	form.AddInputField("Image", card.Image, 50, nil, func(text string) {
+		card.Image = text
+	})
+	form.AddInputField("Source", card.Source, 50, nil, func(text string) {
+		card.Source = text
+	})
+	form.AddInputField("Tags", strings.Join(card.Tags, ", "), 50, nil, func(text string) {
+		card.Tags = parseTags(text)
+	})
+	form.AddTextArea("Notes", card.Notes, 50, 4, 0, func(text string) {
+		card.Notes = text
+	})
+	original := card
+	form.AddButton("Save", func() {
+		card.Notes = strings.TrimSpace(card.Notes)
+		if card.HSKLevel < 0 || card.HSKLevel > maxHSKLevel {
+			card.HSKLevel = 0
+		}
+		if a.Config.KeepHistory {
+			card.RecordRevision(original, time.Now())
+		}
+		if err := a.UpdateCard(card); err != nil {
+			a.Application.SetRoot(a.MainView, true)
+			a.SetStatus(a.errorStatus("Error saving card", err))
+			return
+		}
+		a.Application.SetRoot(a.MainView, true)
+		a.UpdateCardView()
+	})
+	form.AddButton("Cancel", func() {
+		a.Application.SetRoot(a.MainView, true)
+	})
+
+	form.SetBorder(true).
+		SetTitle(" Edit Card ").
+		SetTitleAlign(tview.AlignCenter)
+
+	a.Application.SetRoot(centered(form), true)
+}
+
+// hskLevelText formats an HSK level for the edit form, empty if unknown
+func hskLevelText(level int) string {
+	if level == 0 {
+		return ""
+	}
+	return strconv.Itoa(level)
+}
+
+// UpdateCard replaces the card with the same ID and rewrites the file
+func (a *App) UpdateCard(card chinese.Flashcard) error {
+	return a.mutateDeck(func() error {
+		if err := a.checkWritable(); err != nil {
+			return err
+		}
+		idx := a.cardIndex(card.ID)
+		if idx < 0 {
+			return ErrCardNotFound
+		}
+		a.snapshot()
+		a.Deck[idx] = card
+		a.Logger.Info("card updated", "id", card.ID)
+		return a.rewriteDeck()
+	})
+}
+
+// ShowPinyinEditor displays a single line to correct the current card's Pinyin, e.g. a wrong
+// tone, leaving the other fields alone. Enter saves it and Escape cancels.
+func (a *App) ShowPinyinEditor() {
+	a.showLineEditor(" Correct Pinyin ", "Pinyin: ", func(card chinese.Flashcard) string {
+		return card.Pinyin
+	}, func(card *chinese.Flashcard, text string) string {
+		pinyin := strings.TrimSpace(text)
+		if pinyin == "" || pinyin == card.Pinyin {
+			return ""
+		}
+		card.Pinyin = pinyin
+		return "Pinyin corrected"
+	})
+}
+
+// ToggleSpoken switches between the dictionary Pinyin and the Pinyin as spoken after tone sandhi
+func (a *App) ToggleSpoken() {
+	a.ShowSpoken = !a.ShowSpoken
+	a.UpdateCardView()
+}
+
+// ToggleNotes shows or hides the notes section of the card view
+func (a *App) ToggleNotes() {
+	a.ShowNotes = !a.ShowNotes
+	a.UpdateCardView()
+}
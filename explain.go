@@ -0,0 +1,59 @@
+// explain.go
+package main
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ShowExplanation asks the AI to explain the current card's translation and shows the
+// explanation as it arrives. Escape closes the view and stops the request.
+func (a *App) ShowExplanation() {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+
+	view := tview.NewTextView().
+		SetWordWrap(true).
+		SetScrollable(true).
+		SetText("Asking the AI...")
+	view.SetBorder(true).
+		SetTitle(" Explain " + card.Chinese + " (Escape to close) ").
+		SetTitleAlign(tview.AlignCenter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			cancel()
+			a.Application.SetRoot(a.MainView, true)
+			a.UpdateCardView()
+			return nil
+		}
+		// Other keys scroll the explanation
+		return event
+	})
+
+	go func() {
+		defer a.recoverPanic()
+		started := false
+		err := a.AI.Explain(ctx, card, func(text string) {
+			a.Application.QueueUpdateDraw(func() {
+				if !started {
+					view.Clear()
+					started = true
+				}
+				view.Write([]byte(text))
+			})
+		})
+		if err != nil && ctx.Err() == nil {
+			a.Application.QueueUpdateDraw(func() {
+				view.Write([]byte("\n\nError: " + err.Error()))
+			})
+		}
+	}()
+
+	a.Application.SetRoot(centered(view), true)
+}
@@ -0,0 +1,35 @@
+// export.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"chinese/pkg/chinese"
+)
+
+// ExportPretty writes the cards of a deck file as an indented JSON array, which is easier
+// to read and edit by hand than JSONL but can't be loaded as a deck, and reports the result to out
+func ExportPretty(deckFile, outFile string, names FieldNames, out io.Writer) error {
+	cards, err := readCards(deckFile, names)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", deckFile, err)
+	}
+	if cards == nil {
+		// An empty deck is exported as [] rather than null
+		cards = []chinese.Flashcard{}
+	}
+
+	b, err := json.MarshalIndent(cards, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outFile, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", outFile, err)
+	}
+
+	fmt.Fprintf(out, "Exported %d cards to %s\n", len(cards), outFile)
+	return nil
+}
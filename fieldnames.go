@@ -0,0 +1,85 @@
+// fieldnames.go
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"chinese/pkg/chinese"
+)
+
+// FieldNames maps standard card fields (e.g. "en") to the names used in a
+// flashcards file (e.g. "english"), so existing files can be loaded as they are
+type FieldNames map[string]string
+
+// cardFields returns the standard JSON field names of a card
+func cardFields() []string {
+	var fields []string
+	t := reflect.TypeOf(chinese.Flashcard{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// Validate checks every mapping renames a standard field to a distinct, non-empty name
+func (f FieldNames) Validate() error {
+	standard := make(map[string]bool)
+	for _, field := range cardFields() {
+		standard[field] = true
+	}
+
+	// Sorted so the same config always reports the same error
+	keys := make([]string, 0, len(f))
+	for field := range f {
+		keys = append(keys, field)
+	}
+	sort.Strings(keys)
+
+	used := make(map[string]string)
+	for _, field := range keys {
+		name := f[field]
+		switch {
+		case !standard[field]:
+			return fmt.Errorf("unknown field %q in field_names, expected one of %s", field, strings.Join(cardFields(), ", "))
+		case name == "":
+			return fmt.Errorf("empty name for field %q in field_names", field)
+		case used[name] != "":
+			return fmt.Errorf("fields %q and %q are both named %q in field_names", used[name], field, name)
+		case standard[name] && name != field:
+			return fmt.Errorf("field %q can't be named %q, which is the name of another field", field, name)
+		}
+		used[name] = field
+	}
+	return nil
+}
+
+// renames reports whether any field has a custom name
+func (f FieldNames) renames() bool {
+	for field, name := range f {
+		if name != field {
+			return true
+		}
+	}
+	return false
+}
+
+// remap renames the custom fields of a card stored as a JSON object to the standard names.
+// Standard names are kept, so files saved with them load with or without the mapping.
+func (f FieldNames) remap(card map[string]any) {
+	for field, name := range f {
+		value, ok := card[name]
+		if !ok || name == field {
+			continue
+		}
+		delete(card, name)
+		if _, ok := card[field]; !ok {
+			card[field] = value
+		}
+	}
+}
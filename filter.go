@@ -0,0 +1,216 @@
+// filter.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"chinese/pkg/chinese"
+)
+
+// SetFilter limits the review session to the cards matching filter, or all cards if filter
+// is nil, and moves to the first matching card. The name describes the filter in the card
+// view. It returns the number of matching cards.
+func (a *App) SetFilter(name string, filter func(chinese.Flashcard) bool) int {
+	var matching int
+	a.mutateDeck(func() error {
+		a.queue.filter = filter
+		a.queue.sync(a.Deck)
+		matching = len(a.queue.ids)
+		if matching > 0 && len(a.Deck) > 0 && !a.queue.contains(a.Deck[a.CurrentCardIdx].ID) {
+			a.CurrentCardIdx = a.cardIndex(a.queue.ids[0])
+		}
+		return nil
+	})
+	a.FilterName = name
+	a.RevealStep = 0
+	a.Unmasked = false
+	return matching
+}
+
+// addedBetween returns a filter matching cards created in the given range of local dates,
+// either of which may be zero for an open range. Cards without a creation time never match.
+func addedBetween(since, until time.Time) func(chinese.Flashcard) bool {
+	return func(card chinese.Flashcard) bool {
+		if card.CreatedAt == nil {
+			return false
+		}
+		created := card.CreatedAt.Local()
+		return (since.IsZero() || !created.Before(since)) &&
+			(until.IsZero() || created.Before(until.AddDate(0, 0, 1)))
+	}
+}
+
+// dateRangeName describes a creation date range
+func dateRangeName(since, until time.Time) string {
+	switch {
+	case until.IsZero():
+		return "added since " + since.Format(dateLayout)
+	case since.IsZero():
+		return "added until " + until.Format(dateLayout)
+	default:
+		return "added " + since.Format(dateLayout) + " to " + until.Format(dateLayout)
+	}
+}
+
+// maxHSKLevel is the highest level of the HSK 3.0 exam
+const maxHSKLevel = 9
+
+// hskBetween returns a filter matching cards whose HSK level is in the given inclusive range,
+// and cards without a level if includeUnknown is set
+func hskBetween(lowest, highest int, includeUnknown bool) func(chinese.Flashcard) bool {
+	return func(card chinese.Flashcard) bool {
+		if card.HSKLevel == 0 {
+			return includeUnknown
+		}
+		return card.HSKLevel >= lowest && card.HSKLevel <= highest
+	}
+}
+
+// hskRangeName describes an HSK level range
+func hskRangeName(lowest, highest int, includeUnknown bool) string {
+	name := fmt.Sprintf("at HSK levels %d to %d", lowest, highest)
+	if lowest == highest {
+		name = fmt.Sprintf("at HSK level %d", lowest)
+	}
+	if includeUnknown {
+		name += " or without a level"
+	}
+	return name
+}
+
+// validateHSKRange checks an HSK level range given on the command line
+func validateHSKRange(lowest, highest int) error {
+	if lowest < 1 || highest > maxHSKLevel {
+		return fmt.Errorf("HSK levels go from 1 to %d", maxHSKLevel)
+	}
+	if lowest > highest {
+		return fmt.Errorf("--hsk-min %d is above --hsk-max %d", lowest, highest)
+	}
+	return nil
+}
+
+// allOf returns a filter matching the cards matched by every one of the filters
+func allOf(filters ...func(chinese.Flashcard) bool) func(chinese.Flashcard) bool {
+	return func(card chinese.Flashcard) bool {
+		for _, filter := range filters {
+			if !filter(card) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// defaultRecentCards is how many cards the recently added filter keeps when unconfigured
+const defaultRecentCards = 20
+
+// ToggleRecentCards limits the session to the most recently added cards, or lifts the
+// filter if one is set
+func (a *App) ToggleRecentCards() {
+	if a.FilterName != "" {
+		a.SetFilter("", nil)
+		a.SetStatus("Reviewing all cards")
+		return
+	}
+
+	n := a.Config.RecentCards
+	if n <= 0 {
+		n = defaultRecentCards
+	}
+	var recent map[int]bool
+	a.readDeck(func() {
+		recent = recentlyAdded(a.Deck, n)
+	})
+	name := fmt.Sprintf("among the %d added last", n)
+	a.SetFilter(name, func(card chinese.Flashcard) bool {
+		return recent[card.ID]
+	})
+	a.UpdateCardView()
+}
+
+// recentlyAdded returns the IDs of the n cards added last: by creation time, or by
+// position in the file for cards without one, which count as older
+func recentlyAdded(deck []chinese.Flashcard, n int) map[int]bool {
+	order := make([]int, len(deck))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		var ci, cj time.Time
+		if deck[i].CreatedAt != nil {
+			ci = *deck[i].CreatedAt
+		}
+		if deck[j].CreatedAt != nil {
+			cj = *deck[j].CreatedAt
+		}
+		return ci.Compare(cj)
+	})
+
+	recent := make(map[int]bool, n)
+	for _, i := range order[max(len(order)-n, 0):] {
+		recent[deck[i].ID] = true
+	}
+	return recent
+}
+
+// parseDate parses a local date, given as YYYY-MM-DD or as a number of days ago such as 7d
+func parseDate(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if n, ok := strings.CutSuffix(value, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err != nil || days < 0 {
+			return time.Time{}, fmt.Errorf("invalid number of days %q", value)
+		}
+		year, month, day := now.AddDate(0, 0, -days).Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location()), nil
+	}
+	date, err := time.ParseInLocation(dateLayout, value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or a number of days like 7d", value)
+	}
+	return date, nil
+}
+
+// BackfillCreated gives the cards without a creation time one based on their position
+// in the file: the creation time of the next card that has one, or the modification
+// time of the file for the last cards, so the order of the file is kept. It rewrites
+// the deck and reports the result to out.
+func (a *App) BackfillCreated(out io.Writer) error {
+	latest := time.Now().Truncate(time.Second)
+	if info, err := os.Stat(a.FlashcardsFile); err == nil {
+		latest = info.ModTime().Truncate(time.Second)
+	}
+
+	var filled int
+	err := a.mutateDeck(func() error {
+		if err := a.checkWritable(); err != nil {
+			return err
+		}
+		for i := len(a.Deck) - 1; i >= 0; i-- {
+			if a.Deck[i].CreatedAt != nil {
+				latest = *a.Deck[i].CreatedAt
+				continue
+			}
+			created := latest
+			a.Deck[i].CreatedAt = &created
+			filled++
+		}
+		if filled == 0 {
+			return nil
+		}
+		return a.rewriteDeck()
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Filled in the creation time of %d cards\n", filled)
+	return nil
+}
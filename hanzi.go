@@ -0,0 +1,86 @@
+// hanzi.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"unicode"
+)
+
+// hanziCount is how many Chinese characters a deck contains, and how many of a
+// character list such as the HSK characters it covers
+type hanziCount struct {
+	Cards      int `json:"cards"`
+	Characters int `json:"characters"`
+	Unique     int `json:"unique"`
+
+	// Coverage is only filled in when a character list is given
+	Coverage *hanziCoverage `json:"coverage,omitempty"`
+}
+
+// hanziCoverage is how many of the characters of a list a deck contains
+type hanziCoverage struct {
+	Listed   int     `json:"listed"`
+	Covered  int     `json:"covered"`
+	Fraction float64 `json:"fraction"`
+}
+
+// CountHanzi counts the Chinese characters of a deck file and reports them to out, as
+// text or as JSON. If listFile is set, the unique characters are compared with the
+// Chinese characters it contains, in whatever layout, to report the list's coverage.
+func CountHanzi(deckFile, listFile string, names FieldNames, asJSON bool, out io.Writer) error {
+	cards, err := readCards(deckFile, names)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", deckFile, err)
+	}
+
+	count := hanziCount{Cards: len(cards)}
+	seen := make(map[rune]bool)
+	for _, card := range cards {
+		for _, r := range card.Chinese {
+			if unicode.Is(unicode.Han, r) {
+				count.Characters++
+				seen[r] = true
+			}
+		}
+	}
+	count.Unique = len(seen)
+
+	if listFile != "" {
+		b, err := os.ReadFile(listFile)
+		if err != nil {
+			return err
+		}
+		listed := make(map[rune]bool)
+		for _, r := range string(b) {
+			if unicode.Is(unicode.Han, r) {
+				listed[r] = true
+			}
+		}
+		if len(listed) == 0 {
+			return fmt.Errorf("%s contains no Chinese characters", listFile)
+		}
+		coverage := &hanziCoverage{Listed: len(listed)}
+		for r := range listed {
+			if seen[r] {
+				coverage.Covered++
+			}
+		}
+		coverage.Fraction = float64(coverage.Covered) / float64(coverage.Listed)
+		count.Coverage = coverage
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(count)
+	}
+	fmt.Fprintf(out, "%d cards, %d Chinese characters, %d unique\n", count.Cards, count.Characters, count.Unique)
+	if coverage := count.Coverage; coverage != nil {
+		fmt.Fprintf(out, "Covers %d of the %d characters in %s (%.1f%%)\n",
+			coverage.Covered, coverage.Listed, listFile, 100*coverage.Fraction)
+	}
+	return nil
+}
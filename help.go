@@ -0,0 +1,103 @@
+// help.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// control describes a keyboard shortcut on the main view
+type control struct {
+	Key         string
+	Description string
+	// Footer controls are shown in the footer, the others only in the help overlay
+	Footer bool
+}
+
+// controls lists the main view shortcuts, shown in the footer and the help overlay
+var controls = []control{
+	{"→", "Reveal/Next Card", true},
+	{"n", "New Card", true},
+	{"N", "Add Card from Clipboard", false},
+	{"k", "Skip Card", false},
+	{"j", "Jump", true},
+	{"L", "Card List (Reorder Cards)", false},
+	{"e", "Edit Card", true},
+	{"T", "Edit Tags", false},
+	{"P", "Correct Pinyin", false},
+	{"^", "Pin/Unpin Card", false},
+	{"E", "Explain Translation", false},
+	{"o", "Show/Hide Notes", false},
+	{"t", "Dictionary/Spoken Pinyin", false},
+	{"z", "Show/Hide Stroke Counts", false},
+	{"r", "Reverse Mode (Chinese First)", false},
+	{"c", "Character Practice (Pinyin Shown)", false},
+	{"H", "Translation History", false},
+	{"i", "Show Card JSON", false},
+	{"p", "Play Audio", false},
+	{"1-5", "Rate Confidence (after revealing)", false},
+	{"f", "Focus on Weak Cards", false},
+	{"A", "Focus on Cards Not Seen Lately", false},
+	{"R", "Review Recently Added Cards", false},
+	{"l", "Learn Components", false},
+	{"m", "Multiple-Choice Quiz", false},
+	{"a", "Auto-Play (any key pauses)", false},
+	{"s", "Statistics", false},
+	{"S", "Split Card", false},
+	{"u/Ctrl-R", "Undo/Redo", false},
+	{"w", "Save Deck", false},
+	{"x", "Privacy Mode", false},
+	{"v", "Show/Hide Answer in Privacy Mode", false},
+	{"y/Y", "Copy Chinese/Card", false},
+	{"h", "Hide/Show Controls", false},
+	{"b", "Hide/Show Status Bar", false},
+	{"?", "Help", true},
+	{"q", "Quit", true},
+}
+
+// controlsSummary formats the footer controls on a single line
+func controlsSummary() string {
+	var parts []string
+	for _, c := range controls {
+		if c.Footer {
+			parts = append(parts, c.Key+": "+c.Description)
+		}
+	}
+	return strings.Join(parts, "  |  ")
+}
+
+// ShowHelp displays an overlay listing all controls, closed by any key
+func (a *App) ShowHelp() {
+	var content strings.Builder
+	content.WriteString("\n")
+	for _, c := range controls {
+		content.WriteString(fmt.Sprintf("[::b]%8s[::-]  %s\n", tview.Escape(c.Key), c.Description))
+	}
+	content.WriteString("\nPress any key to close")
+
+	help := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(content.String())
+	help.SetBorder(true).
+		SetTitle(" Help ").
+		SetTitleAlign(tview.AlignCenter)
+	help.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		a.Application.SetRoot(a.MainView, true)
+		return nil
+	})
+
+	a.Application.SetRoot(centered(help), true)
+}
+
+// ToggleControls hides or shows the controls footer and saves the preference
+func (a *App) ToggleControls() {
+	a.Config.HideControls = !a.Config.HideControls
+	if err := SaveConfig(a.ConfigFile, a.Config); err != nil {
+		a.SetStatus(a.errorStatus("Error saving config", err))
+		return
+	}
+	a.UpdateCardView()
+}
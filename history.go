@@ -0,0 +1,57 @@
+// history.go
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// ShowHistory lists the previous translations of the current card, most recent first.
+// Selecting one restores it, keeping the current translation in the history.
+func (a *App) ShowHistory() {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+	if len(card.History) == 0 {
+		if a.Config.KeepHistory {
+			a.SetStatus("This card has no previous translations")
+		} else {
+			a.SetStatus("This card has no previous translations, set keep_history in the config to record them")
+		}
+		return
+	}
+
+	list := tview.NewList()
+	for i := len(card.History) - 1; i >= 0; i-- {
+		rev := card.History[i]
+		list.AddItem(fmt.Sprintf("%s (%s)", rev.ZH, rev.Pinyin),
+			"Replaced "+rev.Replaced.Local().Format("2006-01-02 15:04"), 0, nil)
+	}
+	list.SetSelectedFunc(func(i int, _, _ string, _ rune) {
+		rev := card.History[len(card.History)-1-i]
+		restored := card
+		restored.Chinese, restored.Pinyin = rev.ZH, rev.Pinyin
+		// The spoken Pinyin belonged to the replaced translation
+		restored.SpokenPinyin = ""
+		restored.RecordRevision(card, time.Now())
+
+		a.Application.SetRoot(a.MainView, true)
+		if err := a.UpdateCard(restored); err != nil {
+			a.SetStatus(a.errorStatus("Error restoring translation", err))
+			return
+		}
+		a.SetStatus("Restored " + rev.ZH)
+	})
+	list.SetDoneFunc(func() {
+		a.Application.SetRoot(a.MainView, true)
+	})
+
+	list.SetBorder(true).
+		SetTitle(" Previous Translations ").
+		SetTitleAlign(tview.AlignCenter)
+
+	a.Application.SetRoot(centered(list), true)
+}
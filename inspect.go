@@ -0,0 +1,53 @@
+// inspect.go
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ShowCardJSON displays the current card as it is stored in the flashcards file, followed by
+// its review and quiz stats as stored in the state file. Escape closes it.
+func (a *App) ShowCardJSON() {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+
+	b, err := json.MarshalIndent(card, "", "  ")
+	if err != nil {
+		a.SetStatus(a.errorStatus("Error encoding card", err))
+		return
+	}
+
+	text := "Flashcards file:\n" + string(b) + "\n\nState file:\n"
+	if stats := a.cardStats(card.ID); stats != nil {
+		b, err = json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			a.SetStatus(a.errorStatus("Error encoding card stats", err))
+			return
+		}
+		text += string(b)
+	} else if !a.keepsStats(card.ID) {
+		text += "None are kept for this card, its ID is only for this session"
+	} else {
+		text += "No reviews or quiz answers yet"
+	}
+
+	view := tview.NewTextView().
+		SetText(text + "\n\nPress Escape to close")
+	view.SetBorder(true).
+		SetTitle(" Card JSON ").
+		SetTitleAlign(tview.AlignCenter)
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.Application.SetRoot(a.MainView, true)
+			return nil
+		}
+		return event
+	})
+
+	a.Application.SetRoot(centered(view), true)
+}
@@ -0,0 +1,205 @@
+// jump.go
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+const (
+	// jumpDebounce is how long to wait after the last keystroke before filtering
+	jumpDebounce = 150 * time.Millisecond
+	// jumpMaxResults caps the number of matches listed in the jump dialog
+	jumpMaxResults = 20
+)
+
+// jumpMatch is a card matching a jump query
+type jumpMatch struct {
+	Index int
+	Card  chinese.Flashcard
+	Score int
+}
+
+// ShowJumpDialog displays a search box with a live-filtered list of matching cards
+func (a *App) ShowJumpDialog() {
+	input := tview.NewInputField().
+		SetLabel("Search: ").
+		SetFieldWidth(0)
+	results := tview.NewList().
+		ShowSecondaryText(false)
+
+	var matches []jumpMatch
+	var timer *time.Timer
+
+	populate := func(query string) {
+		matches = a.searchCards(query)
+		results.Clear()
+		for _, m := range matches {
+			results.AddItem(fmt.Sprintf("%d: %s  %s", m.Card.ID, tview.Escape(m.Card.English), tview.Escape(m.Card.Chinese)), "", 0, nil)
+		}
+	}
+	jump := func(i int) {
+		if timer != nil {
+			timer.Stop()
+		}
+		if i >= 0 && i < len(matches) {
+			a.jumpTo(matches[i].Index)
+		}
+		a.Application.SetRoot(a.MainView, true)
+		a.UpdateCardView()
+	}
+
+	input.SetChangedFunc(func(text string) {
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(jumpDebounce, func() {
+			a.Application.QueueUpdateDraw(func() {
+				populate(text)
+			})
+		})
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			populate(input.GetText())
+			jump(0)
+		case tcell.KeyEscape:
+			jump(-1)
+		case tcell.KeyTab, tcell.KeyDown:
+			a.Application.SetFocus(results)
+		}
+	})
+	results.SetSelectedFunc(func(i int, _, _ string, _ rune) {
+		jump(i)
+	})
+	results.SetDoneFunc(func() {
+		jump(-1)
+	})
+	populate("")
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(results, 0, 1, false)
+	layout.SetBorder(true).
+		SetTitle(" Jump to Card ").
+		SetTitleAlign(tview.AlignCenter)
+
+	a.Application.SetRoot(centered(layout), true)
+}
+
+// jumpTo makes the card at the given deck index the current, unrevealed card
+func (a *App) jumpTo(idx int) {
+	a.mutateDeck(func() error {
+		if idx < len(a.Deck) {
+			a.CurrentCardIdx = idx
+		}
+		return nil
+	})
+	a.RevealStep = 0
+	a.Unmasked = false
+	a.Status = ""
+}
+
+// searchFields are the fields a search can be limited to with a prefix such as "zh:",
+// named as in the flashcards file
+var searchFields = map[string]func(chinese.Flashcard) string{
+	"en":     func(card chinese.Flashcard) string { return card.English },
+	"zh":     func(card chinese.Flashcard) string { return card.Chinese },
+	"pinyin": func(card chinese.Flashcard) string { return card.Pinyin },
+	"notes":  func(card chinese.Flashcard) string { return card.Notes },
+	"tags":   func(card chinese.Flashcard) string { return strings.Join(card.Tags, " ") },
+	"source": func(card chinese.Flashcard) string { return card.Source },
+}
+
+// searchScope splits a field prefix such as "zh:" off the query and returns the fields
+// to search, all of them if there is no prefix. A query starting with "id:" only matches
+// card IDs, for which no field is returned.
+func searchScope(query string) (string, []func(chinese.Flashcard) string) {
+	if prefix, rest, ok := strings.Cut(query, ":"); ok {
+		if field, known := searchFields[prefix]; known {
+			return strings.TrimSpace(rest), []func(chinese.Flashcard) string{field}
+		}
+		if prefix == "id" {
+			return strings.TrimSpace(rest), nil
+		}
+	}
+	fields := make([]func(chinese.Flashcard) string, 0, len(searchFields))
+	for _, field := range searchFields {
+		fields = append(fields, field)
+	}
+	return query, fields
+}
+
+// searchCards returns the cards matching the query, best matches first.
+// A numeric query matches card IDs; otherwise the fields of the cards are matched
+// fuzzily, only the field named by a prefix such as "zh:" if the query has one.
+func (a *App) searchCards(query string) []jumpMatch {
+	query, fields := searchScope(strings.ToLower(strings.TrimSpace(query)))
+	id, idErr := strconv.Atoi(query)
+	// Tone marks are ignored, so "nihao" finds "nǐ hǎo"
+	query = toneless(query)
+
+	var matches []jumpMatch
+	a.readDeck(func() {
+		for i, card := range a.Deck {
+			score := -1
+			for _, field := range fields {
+				if s := fuzzyScore(query, toneless(strings.ToLower(field(card)))); s >= 0 && (score < 0 || s < score) {
+					score = s
+				}
+			}
+			if idErr == nil && card.ID == id {
+				score = 0
+			}
+			if score >= 0 {
+				matches = append(matches, jumpMatch{Index: i, Card: card, Score: score})
+			}
+		}
+	})
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score < matches[j].Score
+	})
+	if len(matches) > jumpMaxResults {
+		matches = matches[:jumpMaxResults]
+	}
+	return matches
+}
+
+// fuzzyScore reports how well query matches text, lower is better, or -1 for no match.
+// Substring matches rank ahead of matches where the query characters appear in order.
+func fuzzyScore(query, text string) int {
+	if query == "" {
+		return 1
+	}
+	if i := strings.Index(text, query); i >= 0 {
+		return 1 + i
+	}
+
+	// Subsequence match, scored by how spread out the matched characters are
+	pos, first, last := 0, -1, -1
+	runes := []rune(text)
+	for _, q := range query {
+		for pos < len(runes) && runes[pos] != q {
+			pos++
+		}
+		if pos == len(runes) {
+			return -1
+		}
+		if first < 0 {
+			first = pos
+		}
+		last = pos
+		pos++
+	}
+	return len(runes) + last - first
+}
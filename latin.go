@@ -0,0 +1,32 @@
+// latin.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"chinese/pkg/chinese"
+)
+
+// What to do with translations whose Chinese contains Latin words
+const (
+	// LatinAllow accepts them as they are
+	LatinAllow = "allow"
+	// LatinRetry requests the translation again, failing if every attempt has Latin words
+	LatinRetry = "retry"
+	// LatinWarn adds the card but says which words were left in Latin
+	LatinWarn = "warn"
+)
+
+// latinWarning returns a warning about Latin words left in the card's Chinese when
+// latin_in_chinese is set to warn, or "" if there are none
+func (a *App) latinWarning(card chinese.Flashcard) string {
+	if a.Config.LatinInChinese != LatinWarn {
+		return ""
+	}
+	words := chinese.LatinWords(card.Chinese, a.Config.AllowedLatin)
+	if len(words) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Card %d's Chinese contains Latin words: %s", card.ID, strings.Join(words, ", "))
+}
@@ -0,0 +1,46 @@
+// layout.go
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// The smallest terminal the centered layout is readable in. Below it the card fills
+// the screen without a border.
+const (
+	minLayoutWidth  = 60
+	minLayoutHeight = 20
+)
+
+// fitLayout switches between the centered and the compact layout when the terminal
+// crosses the minimum size. It runs before every draw.
+func (a *App) fitLayout(screen tcell.Screen) bool {
+	width, height := screen.Size()
+	compact := width < minLayoutWidth || height < minLayoutHeight
+	if compact != a.compact {
+		a.compact = compact
+		a.layoutMainView()
+		a.UpdateCardView()
+	}
+	return false
+}
+
+// layoutMainView arranges the card view and the status bar for the current layout
+func (a *App) layoutMainView() {
+	a.MainView.Clear()
+	a.CardView.SetBorder(!a.compact)
+	if a.compact {
+		a.MainView.AddItem(a.CardView, 0, 1, true)
+	} else {
+		a.MainView.AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+				AddItem(nil, 0, 1, false).
+				AddItem(a.CardView, 0, 2, true).
+				AddItem(nil, 0, 1, false), 0, 2, true).
+			AddItem(nil, 0, 1, false), 0, 1, true)
+	}
+	a.MainView.AddItem(a.StatusBar, 1, 0, false)
+	a.layoutStatusBar()
+}
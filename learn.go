@@ -0,0 +1,162 @@
+// learn.go
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// bundledDecompositions is a small starter table of common components and characters
+//
+//go:embed decomposition.tsv
+var bundledDecompositions string
+
+// Decomposition describes a character in terms of its components
+type Decomposition struct {
+	Components []string
+	Meaning    string
+}
+
+// ParseDecompositions parses a tab-separated table of character, space-separated
+// components ("-" for none) and meaning. Lines starting with # are ignored.
+func ParseDecompositions(table string, into map[string]Decomposition) error {
+	for i, line := range strings.Split(table, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return fmt.Errorf("line %d: expected 3 tab-separated fields, got %d", i+1, len(fields))
+		}
+
+		var components []string
+		if fields[1] != "-" {
+			components = strings.Fields(fields[1])
+		}
+		into[fields[0]] = Decomposition{
+			Components: components,
+			Meaning:    fields[2],
+		}
+	}
+	return nil
+}
+
+// loadDecompositions returns the bundled table, extended by the configured file if any
+func (a *App) loadDecompositions() (map[string]Decomposition, error) {
+	if a.Decompositions != nil {
+		return a.Decompositions, nil
+	}
+
+	table := make(map[string]Decomposition)
+	if err := ParseDecompositions(bundledDecompositions, table); err != nil {
+		return nil, fmt.Errorf("bundled decomposition table: %w", err)
+	}
+	if a.Config.DecompositionFile != "" {
+		b, err := os.ReadFile(a.Config.DecompositionFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := ParseDecompositions(string(b), table); err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Config.DecompositionFile, err)
+		}
+	}
+
+	a.Decompositions = table
+	return table, nil
+}
+
+// learnSteps returns the learning sequence for the Chinese text:
+// the components of each character, then the character itself
+func learnSteps(chinese string, table map[string]Decomposition) []string {
+	var steps []string
+	seen := make(map[rune]bool)
+	for _, r := range chinese {
+		if !unicode.Is(unicode.Han, r) || seen[r] {
+			continue
+		}
+		seen[r] = true
+
+		char := string(r)
+		entry, ok := table[char]
+		if !ok {
+			steps = append(steps, fmt.Sprintf("[::b]%s[::-]  not in the decomposition table", char))
+			continue
+		}
+		if len(entry.Components) == 0 {
+			steps = append(steps, fmt.Sprintf("[::b]%s[::-]  %s", char, tview.Escape(entry.Meaning)))
+			continue
+		}
+
+		for _, component := range entry.Components {
+			meaning := "?"
+			if c, ok := table[component]; ok {
+				meaning = c.Meaning
+			}
+			steps = append(steps, fmt.Sprintf("  %s  %s", component, tview.Escape(meaning)))
+		}
+		steps = append(steps, fmt.Sprintf("[::b]%s[::-] = %s  %s",
+			char, strings.Join(entry.Components, " + "), tview.Escape(entry.Meaning)))
+	}
+	return steps
+}
+
+// ShowLearnMode walks through the components of the current card's characters, one step per →
+func (a *App) ShowLearnMode() {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+	table, err := a.loadDecompositions()
+	if err != nil {
+		a.SetStatus(a.errorStatus("Error loading decompositions", err))
+		return
+	}
+
+	steps := learnSteps(card.Chinese, table)
+	if len(steps) == 0 {
+		a.SetStatus("No Chinese characters to learn")
+		return
+	}
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true)
+	view.SetBorder(true).
+		SetTitle(" Learn " + card.Chinese + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	shown := 1
+	render := func() {
+		text := strings.Join(steps[:shown], "\n")
+		if shown < len(steps) {
+			text += "\n\n→: Next step  |  Esc: Back"
+		} else {
+			text += "\n\nEsc: Back"
+		}
+		view.SetText(text)
+	}
+	render()
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRight:
+			if shown < len(steps) {
+				shown++
+				render()
+			}
+		case tcell.KeyEscape:
+			a.Application.SetRoot(a.MainView, true)
+		}
+		return nil
+	})
+
+	a.Application.SetRoot(centered(view), true)
+}
@@ -0,0 +1,50 @@
+// lineedit.go
+package main
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// showLineEditor displays a single line to quickly edit one field of the current card,
+// prefilled with value(card). On Enter, apply updates the card from the text and returns
+// the status to show, or "" if nothing changed; the card is then saved. Escape cancels.
+func (a *App) showLineEditor(title, label string, value func(chinese.Flashcard) string, apply func(*chinese.Flashcard, string) string) {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetText(value(card)).
+		SetFieldWidth(0)
+	input.SetDoneFunc(func(key tcell.Key) {
+		a.Application.SetRoot(a.MainView, true)
+		if key != tcell.KeyEnter {
+			return
+		}
+		original := card
+		status := apply(&card, input.GetText())
+		if status == "" {
+			return
+		}
+		if a.Config.KeepHistory {
+			card.RecordRevision(original, time.Now())
+		}
+		if err := a.UpdateCard(card); err != nil {
+			a.SetStatus(a.errorStatus("Error saving card", err))
+			return
+		}
+		a.SetStatus(status)
+	})
+	input.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignCenter)
+
+	a.Application.SetRoot(centered(input), true)
+}
@@ -0,0 +1,113 @@
+// lint.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"chinese/pkg/chinese"
+)
+
+// lintCheck looks for one kind of problem in a deck
+type lintCheck struct {
+	Name string
+	// Check returns a description of each problem found
+	Check func(deck []chinese.Flashcard) []string
+	// Fix tells how to fix the problems found, if there is a command for it
+	Fix string
+}
+
+// lintChecks lists the checks run by --lint, in the order they are reported
+var lintChecks = []lintCheck{
+	{"Cards with the same Chinese", lintHomographs, ""},
+	{"Cards whose Pinyin doesn't match the number of characters", lintSyllables, "Re-translate them with --retranslate=syllables"},
+}
+
+// LintDeck runs every lint check on the cards of a deck file and reports the problems
+// found to out. It returns the number of problems.
+func LintDeck(deckFile string, names FieldNames, out io.Writer) (int, error) {
+	cards, err := readCards(deckFile, names)
+	if err != nil {
+		return 0, fmt.Errorf("reading %s: %w", deckFile, err)
+	}
+
+	var problems int
+	for _, check := range lintChecks {
+		found := check.Check(cards)
+		if len(found) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "%s (%d):\n", check.Name, len(found))
+		for _, problem := range found {
+			fmt.Fprintf(out, "  %s\n", problem)
+		}
+		if check.Fix != "" {
+			fmt.Fprintf(out, "  %s\n", check.Fix)
+		}
+		problems += len(found)
+	}
+	if problems == 0 {
+		fmt.Fprintf(out, "No problems found in %d cards\n", len(cards))
+	}
+	return problems, nil
+}
+
+// lintHomographs reports cards sharing their Chinese with different English, which are
+// hard to tell apart when reviewing in reverse
+func lintHomographs(deck []chinese.Flashcard) []string {
+	var problems []string
+	for _, group := range homographs(deck) {
+		meanings := make([]string, len(group))
+		for i, card := range group {
+			meanings[i] = fmt.Sprintf("%d %q", card.ID, card.English)
+		}
+		problems = append(problems, homographKey(group[0].Chinese)+": cards "+strings.Join(meanings, ", "))
+	}
+	return problems
+}
+
+// lintSyllables reports cards whose Pinyin has far more or fewer syllables than the
+// Chinese has characters, a common sign of a bad translation
+func lintSyllables(deck []chinese.Flashcard) []string {
+	var problems []string
+	for _, card := range deck {
+		if characters, syllables, ok := syllableMismatch(card); ok {
+			problems = append(problems, fmt.Sprintf("%d %q: %s but %s in %q",
+				card.ID, card.English, plural(characters, "character"), plural(syllables, "Pinyin syllable"), card.Pinyin))
+		}
+	}
+	return problems
+}
+
+// syllableMismatch compares the number of Chinese characters of a card with the number
+// of syllables of its Pinyin, which should match. Erhua, as in 一点儿 yìdiǎnr, and
+// ambiguous syllable boundaries in Pinyin written without apostrophes make the count
+// loose, so ok is only set when they differ by more than 1, or a quarter of the
+// characters for longer cards. Cards with Latin letters or digits in the Chinese,
+// which may or may not be spelled out in the Pinyin, are never reported.
+func syllableMismatch(card chinese.Flashcard) (characters, syllables int, ok bool) {
+	for _, r := range card.Chinese {
+		if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return 0, 0, false
+		}
+		if unicode.Is(unicode.Han, r) {
+			characters++
+		}
+	}
+	if characters == 0 {
+		return 0, 0, false
+	}
+	syllables = countSyllables(card.Pinyin)
+	tolerance := max(1, characters/4)
+	return characters, syllables, syllables < characters-tolerance || syllables > characters+tolerance
+}
+
+// plural formats a count of a noun, adding an s unless there is exactly one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,39 @@
+// log.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// defaultLogLevel is the level logged at when --log-level isn't given
+const defaultLogLevel = "warn"
+
+// discardLogger is used when no log file is given
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// OpenLog creates a logger appending to the file, recording events at the given level
+// (debug, info, warn or error) and above, and returns the file to close when done.
+// Without a file, a logger that discards everything and no file are returned.
+func OpenLog(filename, level string) (*slog.Logger, *os.File, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, nil, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", level)
+	}
+	if filename == "" {
+		return discardLogger, nil, nil
+	}
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, nil, err
+	}
+	return slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: minLevel})), file, nil
+}
+
+// errorStatus logs the error and returns the status line reporting it to the user
+func (a *App) errorStatus(message string, err error) string {
+	a.Logger.Error(message, "error", err)
+	return message + ": " + err.Error()
+}
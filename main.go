@@ -5,35 +5,351 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"chinese/pkg/chinese"
 )
 
 func main() {
 	apiKey := flag.String("api-key", "", "OpenAI API key (required)")
-	filePath := flag.String("file", "flashcards.jsonl", "Path to flashcards file")
+	apiKeyFile := flag.String("api-key-file", "", "Read the OpenAI API key from this file")
+	apiKeyCommand := flag.String("api-key-command", "", "Read the OpenAI API key from the output of this shell command")
+	filePath := flag.String("file", "flashcards.jsonl", "Path or http(s) URL of the flashcards file, or several paths separated by commas to review them together")
+	localFile := flag.String("local-file", "", "File to save changes to when --file is a URL (read-only if unset)")
 	model := flag.String("model", "gpt-4o-mini", "OpenAI model to use")
+	reasoningEffort := flag.String("reasoning-effort", "", "Reasoning effort for reasoning models: minimal, low, medium or high (o-series and gpt-5 models only)")
+	verbosity := flag.String("verbosity", "", "Response verbosity: low, medium or high (gpt-5 models only)")
+	organization := flag.String("organization", "", "OpenAI organization ID sent with requests (uses the config value if unset)")
+	project := flag.String("project", "", "OpenAI project ID sent with requests (uses the config value if unset)")
+	var seed *int
+	flag.Func("seed", "Seed sent with translation requests, so the same sentence gets the same translation where the model supports it", func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		seed = &n
+		return nil
+	})
+	statePath := flag.String("state", "state.json", "Path to the file recording study progress")
+	configPath := flag.String("config", "config.json", "Path to configuration file")
+	themeName := flag.String("theme", "", "Color theme to use (dark, light, high-contrast)")
+	revealName := flag.String("reveal", "", "How to reveal the answer: all (Chinese and Pinyin together), staged (Pinyin first) or characters (Pinyin shown from the start)")
+	rpm := flag.Int("rpm", 0, "Maximum translation requests per minute (0 uses the config value, unlimited by default)")
+	retranslate := flag.String("retranslate", "", "Re-translate the cards matching a filter (all, empty, invalid or syllables) and exit")
+	mergeFiles := flag.String("merge", "", "Merge two deck files, given as a.jsonl,b.jsonl, into --out and exit")
+	mergeOut := flag.String("out", "merged.jsonl", "Output file for --merge")
+	check := flag.Bool("check", false, "Check that the API key works and the model is available, then exit")
+	lint := flag.Bool("lint", false, "Report problems with the cards of the deck, such as cards with the same Chinese, and exit")
+	hanzi := flag.Bool("hanzi", false, "Count the Chinese characters of the deck, unique and in total, and exit")
+	hanziList := flag.String("hanzi-list", "", "With --hanzi, a file of characters (e.g. the HSK list) to report the deck's coverage of")
+	asJSON := flag.Bool("json", false, "With --hanzi, print the counts as JSON")
+	exportPretty := flag.String("export-pretty", "", "Export the deck as an indented JSON array to this file and exit")
+	targetCount := flag.Int("target-count", 0, "End the session with a summary once this many cards have been reviewed")
+	logFile := flag.String("log-file", "", "Append a log of deck changes, API requests and errors to this file")
+	logLevel := flag.String("log-level", defaultLogLevel, "Lowest level logged to --log-file: debug, info, warn or error")
+	newCardPosition := flag.String("new-card-position", NewCardAppend, "Where new cards go in the file: append (fast) or prepend (rewrites the whole file)")
+	watch := flag.Bool("watch", false, "Reload the deck when the flashcards file is changed by another program, such as a text editor")
+	noExample := flag.Bool("no-example", false, "Don't send the built-in example translation with each request, saving about 75 input tokens per translation at the risk of less consistent output")
+	durable := flag.Bool("durable", false, "Sync the deck file to disk after every change, so a crash or power loss can't lose the last card (slower)")
+	noMouse := flag.Bool("no-mouse", false, "Don't capture the mouse, so card text can be selected with the terminal's own selection")
+	addedSince := flag.String("added-since", "", "Only review cards added on or after this date (YYYY-MM-DD, or 7d for the last 7 days)")
+	addedUntil := flag.String("added-until", "", "Only review cards added on or before this date (YYYY-MM-DD)")
+	hskMin := flag.Int("hsk-min", 0, "Only review cards at this HSK level or above (1 to 9)")
+	hskMax := flag.Int("hsk-max", 0, "Only review cards at this HSK level or below (1 to 9)")
+	includeUnknown := flag.Bool("include-unknown", false, "With --hsk-min or --hsk-max, also review cards without an HSK level")
+	backfillCreated := flag.Bool("backfill-created", false, "Give cards without a creation date one based on their position in the file and exit")
+	serveAddr := flag.String("serve", "", "Serve the deck as a JSON API on this address (e.g. :8080) instead of starting the UI")
 	flag.Parse()
 
-	if *apiKey == "" {
-		*apiKey = os.Getenv("OPENAI_API_KEY")
-		if *apiKey == "" {
-			fmt.Println("Please provide an API key")
+	config, err := LoadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	theme, err := config.ResolveTheme(*themeName)
+	if err != nil {
+		fmt.Printf("Error loading theme: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Flags take precedence over the config file without being saved to it
+	if *revealName == "" {
+		*revealName = config.Reveal
+	}
+	revealStyle, err := ParseRevealStyle(*revealName)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *newCardPosition != NewCardAppend && *newCardPosition != NewCardPrepend {
+		fmt.Printf("Error in --new-card-position: expected %s or %s, not %q\n", NewCardAppend, NewCardPrepend, *newCardPosition)
+		os.Exit(1)
+	}
+	if *watch && (isURL(*filePath) || strings.Contains(*filePath, ",")) {
+		fmt.Println("Error in --watch: only a single local flashcards file can be watched")
+		os.Exit(1)
+	}
+	if *newCardPosition == NewCardPrepend && strings.Contains(*filePath, ",") {
+		fmt.Println("Error in --new-card-position: new cards can't be prepended to a deck combined from several files")
+		os.Exit(1)
+	}
+
+	if *mergeFiles != "" {
+		files := strings.Split(*mergeFiles, ",")
+		if len(files) != 2 {
+			fmt.Println("Please provide two files to merge, separated by a comma")
+			os.Exit(1)
+		}
+		if err := MergeFiles(files[0], files[1], *mergeOut, config.FieldNames, os.Stdout); err != nil {
+			fmt.Printf("Error merging decks: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *exportPretty != "" {
+		if err := ExportPretty(*filePath, *exportPretty, config.FieldNames, os.Stdout); err != nil {
+			fmt.Printf("Error exporting deck: %v\n", err)
 			os.Exit(1)
 		}
+		return
+	}
+
+	if *lint {
+		problems, err := LintDeck(*filePath, config.FieldNames, os.Stdout)
+		if err != nil {
+			fmt.Printf("Error checking deck: %v\n", err)
+			os.Exit(1)
+		}
+		if problems > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *hanzi {
+		if err := CountHanzi(*filePath, *hanziList, config.FieldNames, *asJSON, os.Stdout); err != nil {
+			fmt.Printf("Error counting characters: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	*apiKey, err = resolveAPIKey(*apiKey, *apiKeyFile, *apiKeyCommand)
+	if err != nil {
+		fmt.Printf("Error reading API key: %v\n", err)
+		os.Exit(1)
+	}
+	if *apiKey == "" {
+		fmt.Println("Please provide an API key")
+		os.Exit(1)
+	}
+
+	// New models appear all the time, so an unknown model is only a warning
+	var modelWarning string
+	if err := chinese.CheckModel(*model, config.KnownModels); err != nil {
+		modelWarning = "Warning: " + err.Error()
+		fmt.Println(modelWarning)
 	}
 
 	app := NewApp(*apiKey, *model)
+	logger, logOutput, err := OpenLog(*logFile, *logLevel)
+	if err != nil {
+		fmt.Printf("Error opening log: %v\n", err)
+		os.Exit(1)
+	}
+	if logOutput != nil {
+		defer logOutput.Close()
+	}
+	app.Logger = logger
+	app.AI.Logger = logger
+	app.Status = modelWarning
+	app.AI.ReasoningEffort = *reasoningEffort
+	app.AI.Verbosity = *verbosity
+	app.AI.Seed = seed
+	app.AI.Sandhi = config.Sandhi
+	app.AI.Examples = config.Examples
+	app.AI.OmitExample = *noExample
+	app.AI.RejectLatin = config.LatinInChinese == LatinRetry
+	app.AI.AllowedLatin = config.AllowedLatin
+	if *organization == "" {
+		*organization = config.Organization
+	}
+	if *project == "" {
+		*project = config.Project
+	}
+	app.AI.Organization = *organization
+	app.AI.Project = *project
+	if *rpm == 0 {
+		*rpm = config.RequestsPerMinute
+	}
+	if *rpm > 0 {
+		app.AI.Limiter = chinese.NewRateLimiter(*rpm)
+	}
+	app.Config = config
+	app.StateFile = *statePath
+	if app.State, err = LoadState(*statePath); err != nil {
+		fmt.Printf("Error loading state: %v\n", err)
+		os.Exit(1)
+	}
+	if banner := app.streakBanner(); banner != "" && app.Status == "" {
+		app.Status = banner
+	}
+	app.ConfigFile = *configPath
+	app.Durable = *durable
+	app.NewCardPosition = *newCardPosition
+	app.TargetCount = *targetCount
+	app.RevealStyle = revealStyle
+	app.Theme = theme
+	app.Audio = NewAudioLibrary(config.AudioDir, config.AudioPlayer)
+	if app.templates, err = parseCardTemplates(config.FrontTemplate, config.BackTemplate); err != nil {
+		fmt.Printf("Error in card templates: %v\n", err)
+		os.Exit(1)
+	}
+	if err := app.LoadSegmenter(); err != nil {
+		fmt.Printf("Error loading word file: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *check {
+		if err := app.CheckConnection(os.Stdout); err != nil {
+			fmt.Printf("Check failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Load the deck
-	if err := app.LoadDeck(*filePath); err != nil {
+	if isURL(*filePath) {
+		err = app.LoadDeckURL(*filePath, *localFile)
+	} else if strings.Contains(*filePath, ",") {
+		err = app.LoadDecks(strings.Split(*filePath, ","))
+	} else {
+		err = app.LoadDeck(*filePath)
+	}
+	if err != nil {
+		app.Logger.Error("Error loading deck", "file", *filePath, "error", err)
 		fmt.Printf("Error loading deck: %v\n", err)
 		os.Exit(1)
 	}
+	app.Logger.Info("deck loaded", "file", *filePath, "cards", len(app.Deck))
 
+	if *backfillCreated {
+		if err := app.BackfillCreated(os.Stdout); err != nil {
+			fmt.Printf("Error filling in creation dates: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Filters given on the command line limit the session to the cards matching all of them
+	var filterNames []string
+	var filters []func(chinese.Flashcard) bool
+	if *addedSince != "" || *addedUntil != "" {
+		now := time.Now()
+		since, err := parseDate(*addedSince, now)
+		if err != nil {
+			fmt.Printf("Error in --added-since: %v\n", err)
+			os.Exit(1)
+		}
+		until, err := parseDate(*addedUntil, now)
+		if err != nil {
+			fmt.Printf("Error in --added-until: %v\n", err)
+			os.Exit(1)
+		}
+		filterNames = append(filterNames, dateRangeName(since, until))
+		filters = append(filters, addedBetween(since, until))
+	}
+	if *hskMin != 0 || *hskMax != 0 {
+		lowest, highest := *hskMin, *hskMax
+		if lowest == 0 {
+			lowest = 1
+		}
+		if highest == 0 {
+			highest = maxHSKLevel
+		}
+		if err := validateHSKRange(lowest, highest); err != nil {
+			fmt.Printf("Error in --hsk-min/--hsk-max: %v\n", err)
+			os.Exit(1)
+		}
+		filterNames = append(filterNames, hskRangeName(lowest, highest, *includeUnknown))
+		filters = append(filters, hskBetween(lowest, highest, *includeUnknown))
+	}
+	if len(filters) > 0 {
+		name := strings.Join(filterNames, " and ")
+		if app.SetFilter(name, allOf(filters...)) == 0 {
+			fmt.Printf("No cards to review: none were %s\n", name)
+			if *addedSince != "" || *addedUntil != "" {
+				fmt.Println("Cards added before creation dates were recorded can be dated with --backfill-created")
+			}
+			os.Exit(1)
+		}
+	}
+
+	if *retranslate != "" {
+		if err := app.Retranslate(*retranslate, os.Stdin, os.Stdout); err != nil {
+			fmt.Printf("Error re-translating cards: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *serveAddr != "" {
+		if err := app.Serve(*serveAddr); err != nil {
+			fmt.Printf("Error running server: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	app.StartQueue()
 	app.SetupUI()
 	app.Application.SetInputCapture(app.HandleInput)
+	app.Application.EnableMouse(!*noMouse)
+
+	app.Application.SetRoot(app.MainView, true)
+	if len(app.Deck) == 0 {
+		app.ShowWelcome()
+	}
 
-	if err := app.Application.SetRoot(app.MainView, true).Run(); err != nil {
+	if *watch {
+		app.WatchDeck()
+	}
+	app.handleSignals()
+	defer app.recoverPanic()
+	err = app.Application.Run()
+	app.Shutdown(os.Stdout)
+	if err != nil {
 		fmt.Printf("Error running application: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// resolveAPIKey returns the API key from, in order of precedence, the flag,
+// the key file, the output of the key command or the OPENAI_API_KEY variable
+func resolveAPIKey(key, file, command string) (string, error) {
+	if key != "" {
+		return key, nil
+	}
+	if file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	if command != "" {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Stderr = os.Stderr
+		b, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("running %q: %w", command, err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return os.Getenv("OPENAI_API_KEY"), nil
+}
@@ -0,0 +1,122 @@
+// merge.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"chinese/pkg/chinese"
+)
+
+// MergeStats summarizes the result of merging two decks
+type MergeStats struct {
+	// Merged is the number of cards present in both decks
+	Merged int
+	// Added is the number of cards only present in the second deck
+	Added int
+	// Conflicted is the number of merged cards whose translations differ
+	Conflicted int
+	// Renumbered maps the old IDs of added cards given a new ID to avoid a collision to
+	// their new ID. Stats in a state file stay under the old ID.
+	Renumbered map[int]int
+}
+
+// MergeDecks combines two decks, matching cards by their English text.
+// When a card is in both decks the one with more information filled in is kept,
+// preferring the first deck on ties. Review history can't decide, as the state file
+// holding it belongs to neither deck in particular. Cards only in the second deck are
+// appended, renumbered if their ID is already taken.
+func MergeDecks(first, second []chinese.Flashcard) ([]chinese.Flashcard, MergeStats) {
+	stats := MergeStats{Renumbered: make(map[int]int)}
+	merged := make([]chinese.Flashcard, len(first))
+	copy(merged, first)
+
+	byEnglish := make(map[string]int, len(merged))
+	ids := make(map[int]bool, len(merged))
+	nextID := 1
+	for i, card := range merged {
+		byEnglish[normalizeEnglish(card.English)] = i
+		ids[card.ID] = true
+		nextID = max(nextID, card.ID+1)
+	}
+	for _, card := range second {
+		nextID = max(nextID, card.ID+1)
+	}
+
+	for _, card := range second {
+		if i, ok := byEnglish[normalizeEnglish(card.English)]; ok {
+			stats.Merged++
+			existing := merged[i]
+			if existing.Chinese != card.Chinese || existing.Pinyin != card.Pinyin {
+				stats.Conflicted++
+			}
+			if cardDetail(card) > cardDetail(existing) {
+				card.ID = existing.ID
+				merged[i] = card
+			}
+			continue
+		}
+
+		if ids[card.ID] {
+			stats.Renumbered[card.ID] = nextID
+			card.ID = nextID
+			nextID++
+		}
+		ids[card.ID] = true
+		byEnglish[normalizeEnglish(card.English)] = len(merged)
+		merged = append(merged, card)
+		stats.Added++
+	}
+	return merged, stats
+}
+
+// MergeFiles merges two deck files, using the given custom field names, into an output
+// file and reports the result to out
+func MergeFiles(firstFile, secondFile, outFile string, names FieldNames, out io.Writer) error {
+	first, err := readCards(firstFile, names)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", firstFile, err)
+	}
+	second, err := readCards(secondFile, names)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", secondFile, err)
+	}
+
+	merged, stats := MergeDecks(first, second)
+	if err := saveCards(outFile, merged); err != nil {
+		return fmt.Errorf("writing %s: %w", outFile, err)
+	}
+
+	fmt.Fprintf(out, "Wrote %d cards to %s\n", len(merged), outFile)
+	fmt.Fprintf(out, "Merged %d, added %d (%d renumbered), conflicted %d\n",
+		stats.Merged, stats.Added, len(stats.Renumbered), stats.Conflicted)
+	if len(stats.Renumbered) > 0 {
+		fmt.Fprintf(out, "Renumbered cards of %s, whose review stats in a state file stay under the old ID:\n", secondFile)
+		for _, id := range slices.Sorted(maps.Keys(stats.Renumbered)) {
+			fmt.Fprintf(out, "  %d -> %d\n", id, stats.Renumbered[id])
+		}
+	}
+	return nil
+}
+
+// normalizeEnglish returns the English text in the form used to compare cards
+func normalizeEnglish(english string) string {
+	return strings.ToLower(strings.Join(strings.Fields(english), " "))
+}
+
+// cardDetail counts the filled-in fields of a card, used to pick between duplicates
+func cardDetail(card chinese.Flashcard) int {
+	n := 0
+	for _, field := range []string{card.Chinese, card.Pinyin, card.Classifier, card.SpokenPinyin, card.Gloss, card.Notes, card.Mnemonic, card.Image, card.Source, card.AudioPath} {
+		if strings.TrimSpace(field) != "" {
+			n++
+		}
+	}
+	if len(card.Tags) > 0 {
+		n++
+	}
+	return n
+}
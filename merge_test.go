@@ -0,0 +1,31 @@
+// merge_test.go
+package main
+
+import (
+	"maps"
+	"testing"
+
+	"chinese/pkg/chinese"
+)
+
+func TestMergeDecks(t *testing.T) {
+	first := []chinese.Flashcard{
+		{ID: 1, English: "Hello", Chinese: "你好", Pinyin: "Nǐ hǎo"},
+		{ID: 2, English: "Thanks", Chinese: "谢谢", Pinyin: "Xièxie"},
+	}
+	second := []chinese.Flashcard{
+		{ID: 1, English: "hello ", Chinese: "你好", Pinyin: "Nǐ hǎo", Notes: "A greeting"},
+		{ID: 2, English: "Goodbye", Chinese: "再见", Pinyin: "Zàijiàn"},
+	}
+
+	merged, stats := MergeDecks(first, second)
+	if len(merged) != 3 || stats.Merged != 1 || stats.Added != 1 || stats.Conflicted != 0 {
+		t.Fatalf("MergeDecks = %d cards with %+v, want 3 cards, 1 merged and 1 added", len(merged), stats)
+	}
+	if merged[0].ID != 1 || merged[0].Notes != "A greeting" {
+		t.Errorf("merged card = %+v, want the second deck's card with notes under ID 1", merged[0])
+	}
+	if want := map[int]int{2: 3}; !maps.Equal(stats.Renumbered, want) || merged[2].ID != 3 {
+		t.Errorf("renumbered %v with added card ID %d, want %v", stats.Renumbered, merged[2].ID, want)
+	}
+}
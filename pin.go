@@ -0,0 +1,31 @@
+// pin.go
+package main
+
+import "fmt"
+
+// TogglePin pins or unpins the current card. Pinned cards come first in every review
+// session, in deck order, whatever the order of the other cards. The current session
+// keeps its order: the card being pinned is on screen already, and moving it in the
+// queue would send the review back to cards already seen.
+func (a *App) TogglePin() {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+	card.Pinned = !card.Pinned
+	if err := a.UpdateCard(card); err != nil {
+		a.SetStatus(a.errorStatus("Error saving card", err))
+		return
+	}
+	if card.Pinned {
+		a.SetStatus(fmt.Sprintf("Pinned card %d, it comes first from the next session", card.ID))
+	} else {
+		a.SetStatus(fmt.Sprintf("Unpinned card %d", card.ID))
+	}
+}
+
+// isPinned reports whether the card with the given ID is pinned. The caller must hold a.mu.
+func (a *App) isPinned(id int) bool {
+	idx := a.cardIndex(id)
+	return idx >= 0 && a.Deck[idx].Pinned
+}
@@ -0,0 +1,561 @@
+// ai.go
+
+// Package chinese translates English sentences into Chinese flashcards using the OpenAI API.
+package chinese
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// AI handles interactions with the OpenAI API
+type AI struct {
+	APIKey  string
+	Model   string
+	Limiter *RateLimiter
+
+	// BaseURL is the API endpoint, DefaultBaseURL if empty
+	BaseURL string
+	// Client sends the requests, http.DefaultClient if nil
+	Client *http.Client
+
+	// Logger records requests and retries, nothing is logged if nil.
+	// The API key and the contents of requests and responses are never logged.
+	Logger *slog.Logger
+
+	// tokens counts the tokens used by all requests in this session
+	tokens atomic.Int64
+
+	// sleep waits before retrying a request, time.Sleep if nil. Tests replace it to
+	// record the waits instead of sleeping.
+	sleep func(time.Duration)
+
+	// ReasoningEffort and Verbosity are only sent when set, as older models reject them
+	ReasoningEffort string
+	Verbosity       string
+
+	// Examples are extra sample translations sent with every translation request
+	Examples []Example
+
+	// OmitExample leaves out the built-in sample translation sent with every translation
+	// request, saving input tokens at the risk of less consistent output
+	OmitExample bool
+
+	// Seed makes translations reproducible, as far as the model supports it, when set
+	Seed *int
+
+	// Sandhi also requests the Pinyin as spoken, after tone sandhi
+	Sandhi bool
+
+	// RejectLatin treats Chinese containing Latin words as an invalid translation, so it is
+	// requested again. Words in AllowedLatin, such as brand names, are accepted.
+	RejectLatin  bool
+	AllowedLatin []string
+
+	// Organization and Project are sent in the OpenAI-Organization and OpenAI-Project
+	// headers when set, for accounts that belong to several organizations or projects
+	Organization string
+	Project      string
+}
+
+// DefaultBaseURL is the OpenAI API endpoint
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// NewAI creates a new AI instance
+func NewAI(apiKey, model string) *AI {
+	if apiKey == "" || model == "" {
+		panic("OpenAI API key and model must be provided")
+	}
+
+	return &AI{
+		APIKey: apiKey,
+		Model:  model,
+	}
+}
+
+// translateAttempts is how many times a translation is requested before giving up
+const translateAttempts = 3
+
+// ErrEmptyResponse is returned when the API responds with an empty body
+var ErrEmptyResponse = errors.New("empty response from OpenAI API")
+
+// ErrTruncatedResponse is returned when the API response ends before the JSON does,
+// e.g. when the connection is cut
+var ErrTruncatedResponse = errors.New("truncated response from OpenAI API")
+
+// ErrNoChoices is returned when the API responds without any completion
+var ErrNoChoices = errors.New("no choices in OpenAI API response")
+
+// ErrInvalidTranslation is returned when the AI response doesn't look like Chinese and Pinyin
+var ErrInvalidTranslation = errors.New("invalid translation")
+
+// Translate returns the Chinese translation and Pinyin pronunciation of the given English sentence.
+// Responses that fail validation are retried a few times before giving up, as are rate limit and
+// server errors, after a growing delay or as long as the API asks in its Retry-After header.
+func (ai *AI) Translate(sentence string) (Translation, error) {
+	var err error
+	var delay time.Duration
+	for attempt := 0; attempt < translateAttempts; attempt++ {
+		ai.wait(delay)
+
+		var translation Translation
+		translation, err = ai.translate(sentence)
+		if err == nil {
+			return translation, nil
+		}
+
+		var apiErr *APIError
+		switch {
+		case errors.Is(err, ErrInvalidTranslation):
+			delay = 0
+		case errors.As(err, &apiErr) && apiErr.Temporary():
+			delay = max(backoff(attempt), apiErr.RetryAfter)
+			if delay > maxRetryWait {
+				return Translation{}, err
+			}
+		default:
+			return Translation{}, err
+		}
+		if attempt == translateAttempts-1 {
+			break
+		}
+		ai.logger().Warn("retrying translation", "attempt", attempt+1, "delay", delay, "error", err)
+	}
+	return Translation{}, err
+}
+
+// wait sleeps for the given delay before a retry
+func (ai *AI) wait(delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+	if ai.sleep != nil {
+		ai.sleep(delay)
+		return
+	}
+	time.Sleep(delay)
+}
+
+// TokensUsed returns the number of tokens used by all requests in this session
+func (ai *AI) TokensUsed() int64 {
+	return ai.tokens.Load()
+}
+
+// translatePrompt is the system prompt for translation requests
+const translatePrompt = "Translate the provided English sentence into Chinese, including pinyin and Chinese characters. " +
+	"If the English is a single noun, also give its measure word with pinyin, e.g. \"本 (běn)\", as the classifier; " +
+	"otherwise leave the classifier empty. " +
+	"If the English has several distinct meanings that translate differently (e.g. \"bank\"), list each one in senses " +
+	"with a short English gloss, most common first, and use the most common for zh and pinyin; otherwise leave senses empty."
+
+// sandhiPrompt is added to the system prompt when spoken Pinyin is requested
+const sandhiPrompt = " Also give spoken_pinyin: the pinyin as actually pronounced, applying third-tone sandhi " +
+	"and the tone changes of 不 and 一. It is the same as pinyin when no tone changes."
+
+// translate sends a single translation request
+func (ai *AI) translate(sentence string) (Translation, error) {
+	var schema = json.RawMessage([]byte(`{
+      "name": "translation",
+      "strict": true,
+      "schema": {
+        "type": "object",
+        "properties": {
+          "zh": {
+            "type": "string"
+          },
+          "pinyin": {
+            "type": "string"
+          },
+          "classifier": {
+            "type": "string"
+          },
+          "senses": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "zh": {
+                  "type": "string"
+                },
+                "pinyin": {
+                  "type": "string"
+                },
+                "gloss": {
+                  "type": "string"
+                }
+              },
+              "required": [
+                "zh",
+                "pinyin",
+                "gloss"
+              ],
+              "additionalProperties": false
+            }
+          }
+        },
+        "required": [
+          "zh",
+          "pinyin",
+          "classifier",
+          "senses"
+        ],
+        "additionalProperties": false
+      }
+    }`))
+
+	var typicalResponse = `{
+      "zh": "我下周可能有时间，可以吗？",
+      "pinyin": "Wǒ xià zhōu kěnéng yǒu shíjiān, kěyǐ ma?",
+      "classifier": "",
+      "senses": []
+    }`
+
+	prompt := translatePrompt
+	if ai.Sandhi {
+		var err error
+		if schema, err = withStringProperty(schema, "spoken_pinyin"); err != nil {
+			return Translation{}, err
+		}
+		prompt += sandhiPrompt
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: prompt,
+		},
+	}
+	if !ai.OmitExample {
+		messages = append(messages,
+			Message{Role: "user", Content: "I'll probably have time next week. Is that okay?"},
+			Message{Role: "assistant", Content: typicalResponse})
+	}
+	for _, example := range ai.Examples {
+		response, err := json.Marshal(Translation{
+			ZH:         example.ZH,
+			Pinyin:     example.Pinyin,
+			Classifier: example.Classifier,
+			Senses:     []Sense{},
+		})
+		if err != nil {
+			return Translation{}, err
+		}
+		messages = append(messages,
+			Message{Role: "user", Content: example.English},
+			Message{Role: "assistant", Content: string(response)})
+	}
+	messages = append(messages, Message{Role: "user", Content: sentence})
+
+	params := ChatCompletionsParams{
+		Messages: messages,
+		Model:    ai.Model,
+		ResponseFormat: &ResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: schema,
+		},
+		ReasoningEffort: ai.ReasoningEffort,
+		Verbosity:       ai.Verbosity,
+		Seed:            ai.Seed,
+	}
+
+	resp, err := ai.post(context.Background(), params)
+	if err != nil {
+		return Translation{}, err
+	}
+	defer resp.Body.Close()
+
+	var result ChatCompletionsResult
+	b, err := io.ReadAll(resp.Body)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return Translation{}, fmt.Errorf("%w: the connection closed after %d bytes", ErrTruncatedResponse, len(b))
+	}
+	if err != nil {
+		return Translation{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Translation{}, newAPIError(resp, b)
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return Translation{}, ErrEmptyResponse
+	}
+	// A truncated body fails to decode rather than looking like an empty result.
+	// Unlike json.Unmarshal, the decoder tells it apart from malformed JSON.
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(&result); errors.Is(err, io.ErrUnexpectedEOF) {
+		return Translation{}, fmt.Errorf("%w: the JSON ends early after %d bytes", ErrTruncatedResponse, len(b))
+	} else if err != nil {
+		return Translation{}, fmt.Errorf("decoding OpenAI API response: %w", err)
+	}
+
+	ai.tokens.Add(int64(result.Usage.TotalTokens))
+	ai.logger().Debug("translation tokens", "prompt", result.Usage.PromptTokens, "completion", result.Usage.CompletionTokens)
+
+	if len(result.Choices) == 0 {
+		return Translation{}, fmt.Errorf("%w: %s", ErrNoChoices, string(b))
+	}
+
+	var translation Translation
+	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &translation); err != nil {
+		return Translation{}, err
+	}
+
+	translation.ZH = strings.TrimSpace(translation.ZH)
+	translation.Pinyin = strings.TrimSpace(translation.Pinyin)
+	translation.Classifier = strings.TrimSpace(translation.Classifier)
+	translation.SpokenPinyin = strings.TrimSpace(translation.SpokenPinyin)
+	if translation.ZH == "" || translation.Pinyin == "" {
+		return Translation{}, errors.New("no translation found")
+	}
+	if err := ValidateTranslation(translation.ZH, translation.Pinyin); err != nil {
+		return Translation{}, err
+	}
+	if err := ai.checkLatin(translation.ZH); err != nil {
+		return Translation{}, err
+	}
+
+	// Drop malformed senses rather than failing the whole translation
+	senses := translation.Senses[:0]
+	for _, sense := range translation.Senses {
+		sense.ZH = strings.TrimSpace(sense.ZH)
+		sense.Pinyin = strings.TrimSpace(sense.Pinyin)
+		sense.Gloss = strings.TrimSpace(sense.Gloss)
+		if ValidateTranslation(sense.ZH, sense.Pinyin) == nil && ai.checkLatin(sense.ZH) == nil {
+			senses = append(senses, sense)
+		}
+	}
+	translation.Senses = senses
+
+	return translation, nil
+}
+
+// post sends a chat completions request, once the rate limiter allows it
+func (ai *AI) post(ctx context.Context, params ChatCompletionsParams) (*http.Response, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := ai.newRequest(ctx, http.MethodPost, "/chat/completions", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if ai.Limiter != nil {
+		ai.Limiter.Wait()
+	}
+	return ai.do(req)
+}
+
+// newRequest creates an authenticated request to an API path such as "/models"
+func (ai *AI) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	baseURL := ai.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(baseURL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+ai.APIKey)
+	if ai.Organization != "" {
+		req.Header.Set("OpenAI-Organization", ai.Organization)
+	}
+	if ai.Project != "" {
+		req.Header.Set("OpenAI-Project", ai.Project)
+	}
+	return req, nil
+}
+
+// do sends a request with the configured client, logging its outcome
+func (ai *AI) do(req *http.Request) (*http.Response, error) {
+	client := ai.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	log := ai.logger().With("method", req.Method, "path", req.URL.Path, "duration", time.Since(start).Round(time.Millisecond))
+	switch {
+	case err != nil:
+		log.Error("API request failed", "error", err)
+	case resp.StatusCode >= 400:
+		log.Warn("API request", "status", resp.StatusCode, "request_id", resp.Header.Get("X-Request-Id"))
+	default:
+		log.Debug("API request", "status", resp.StatusCode, "request_id", resp.Header.Get("X-Request-Id"))
+	}
+	return resp, err
+}
+
+// logger returns the configured logger, or one that discards everything
+func (ai *AI) logger() *slog.Logger {
+	if ai.Logger == nil {
+		return discardLogger
+	}
+	return ai.Logger
+}
+
+// discardLogger is used when no logger is configured
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// withStringProperty returns the response format schema with an additional required string property
+func withStringProperty(format json.RawMessage, name string) (json.RawMessage, error) {
+	var f struct {
+		Name   string `json:"name"`
+		Strict bool   `json:"strict"`
+		Schema struct {
+			Type                 string                     `json:"type"`
+			Properties           map[string]json.RawMessage `json:"properties"`
+			Required             []string                   `json:"required"`
+			AdditionalProperties bool                       `json:"additionalProperties"`
+		} `json:"schema"`
+	}
+	if err := json.Unmarshal(format, &f); err != nil {
+		return nil, err
+	}
+	f.Schema.Properties[name] = json.RawMessage(`{"type": "string"}`)
+	f.Schema.Required = append(f.Schema.Required, name)
+	return json.Marshal(f)
+}
+
+// ValidateTranslation checks the Chinese contains Chinese characters and the Pinyin is romanized
+func ValidateTranslation(zh, pinyin string) error {
+	zhHan, _ := countScripts(zh)
+	pinyinHan, pinyinLatin := countScripts(pinyin)
+
+	switch {
+	case zhHan == 0 && pinyinHan > 0:
+		return fmt.Errorf("%w: Chinese and Pinyin look transposed (zh %q, pinyin %q)", ErrInvalidTranslation, zh, pinyin)
+	case zhHan == 0:
+		return fmt.Errorf("%w: Chinese %q contains no Chinese characters", ErrInvalidTranslation, zh)
+	case pinyinHan > 0:
+		return fmt.Errorf("%w: Pinyin %q contains Chinese characters", ErrInvalidTranslation, pinyin)
+	case pinyinLatin == 0:
+		return fmt.Errorf("%w: Pinyin %q contains no Latin letters", ErrInvalidTranslation, pinyin)
+	}
+	return nil
+}
+
+// checkLatin returns an invalid translation error if Latin words are rejected and the Chinese has some
+func (ai *AI) checkLatin(zh string) error {
+	if !ai.RejectLatin {
+		return nil
+	}
+	if words := LatinWords(zh, ai.AllowedLatin); len(words) > 0 {
+		return fmt.Errorf("%w: Chinese %q contains Latin words: %s", ErrInvalidTranslation, zh, strings.Join(words, ", "))
+	}
+	return nil
+}
+
+// LatinWords returns the words of ASCII letters in the Chinese, such as English the model left
+// untranslated, except those in the allowed list, compared ignoring case. Numbers are not words.
+func LatinWords(zh string, allowed []string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(zh, func(r rune) bool {
+		return r > unicode.MaxASCII || !unicode.IsLetter(r)
+	}) {
+		if !slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, word) }) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// ValidateExamples checks every example has English, Chinese and Pinyin that pass ValidateTranslation
+func ValidateExamples(examples []Example) error {
+	for i, example := range examples {
+		if strings.TrimSpace(example.English) == "" || strings.TrimSpace(example.ZH) == "" || strings.TrimSpace(example.Pinyin) == "" {
+			return fmt.Errorf("example %d needs en, zh and pinyin", i+1)
+		}
+		if err := ValidateTranslation(example.ZH, example.Pinyin); err != nil {
+			return fmt.Errorf("example %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// countScripts counts the Chinese characters and Latin letters in s
+func countScripts(s string) (han, latin int) {
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	return han, latin
+}
+
+// DefaultKnownModels lists chat models that support structured outputs
+var DefaultKnownModels = []string{
+	"gpt-4o",
+	"gpt-4o-mini",
+	"gpt-4.1",
+	"gpt-4.1-mini",
+	"gpt-4.1-nano",
+	"gpt-5",
+	"gpt-5-mini",
+	"gpt-5-nano",
+	"o1",
+	"o3",
+	"o3-mini",
+	"o4-mini",
+}
+
+// CheckModel returns an error if the model is not one of the known models
+// or a dated snapshot of one (e.g. gpt-4o-2024-08-06)
+func CheckModel(model string, known []string) error {
+	if len(known) == 0 {
+		known = DefaultKnownModels
+	}
+
+	for _, k := range known {
+		if model == k || strings.HasPrefix(model, k+"-20") {
+			return nil
+		}
+	}
+
+	closest, best := "", len(model)
+	for _, k := range known {
+		if d := editDistance(model, k); d < best {
+			closest, best = k, d
+		}
+	}
+	if closest != "" && best <= 3 {
+		return fmt.Errorf("unrecognized model %q, did you mean %q?", model, closest)
+	}
+	return fmt.Errorf("unrecognized model %q", model)
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
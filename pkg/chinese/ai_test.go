@@ -0,0 +1,311 @@
+// ai_test.go
+
+package chinese
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// completion returns a chat completions response whose message content is the given JSON
+func completion(content string) string {
+	b, _ := json.Marshal(map[string]any{
+		"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": content}}},
+		"usage":   map[string]int{"prompt_tokens": 100, "completion_tokens": 20, "total_tokens": 120},
+	})
+	return string(b)
+}
+
+// helloTranslation is the message content of a successful translation of "Hello"
+const helloTranslation = `{"zh": "你好", "pinyin": "Nǐ hǎo", "classifier": "", "senses": []}`
+
+// newTestAI creates an AI sending its requests to a test server with the given handler.
+// Waits before retries are recorded in waits instead of slept.
+func newTestAI(t *testing.T, handler http.HandlerFunc) (ai *AI, waits *[]time.Duration) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	ai = NewAI("test-key", "test-model")
+	ai.BaseURL = server.URL
+	ai.Client = server.Client()
+	waits = new([]time.Duration)
+	ai.sleep = func(d time.Duration) {
+		*waits = append(*waits, d)
+	}
+	return ai, waits
+}
+
+// respond returns a handler answering every request with the status and body
+func respond(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
+
+func TestTranslateSuccess(t *testing.T) {
+	var params ChatCompletionsParams
+	var auth string
+	ai, _ := newTestAI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/chat/completions" {
+			t.Errorf("request to %s %s, want POST /chat/completions", r.Method, r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		respond(http.StatusOK, completion(helloTranslation))(w, r)
+	})
+
+	translation, err := ai.Translate("Hello")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if translation.ZH != "你好" || translation.Pinyin != "Nǐ hǎo" {
+		t.Errorf("translation = %q %q, want 你好 Nǐ hǎo", translation.ZH, translation.Pinyin)
+	}
+	if ai.TokensUsed() != 120 {
+		t.Errorf("TokensUsed() = %d, want 120", ai.TokensUsed())
+	}
+
+	if auth != "Bearer test-key" {
+		t.Errorf("Authorization = %q, want Bearer test-key", auth)
+	}
+	if params.Model != "test-model" {
+		t.Errorf("model = %q, want test-model", params.Model)
+	}
+	messages := params.Messages
+	if len(messages) != 4 {
+		t.Fatalf("got %d messages, want the system prompt, the example pair and the sentence", len(messages))
+	}
+	if messages[0].Role != "system" || messages[0].Content != translatePrompt {
+		t.Errorf("first message = %+v, want the translation system prompt", messages[0])
+	}
+	if last := messages[3]; last.Role != "user" || last.Content != "Hello" {
+		t.Errorf("last message = %+v, want the sentence from the user", last)
+	}
+	if params.ResponseFormat == nil || params.ResponseFormat.Type != "json_schema" {
+		t.Fatalf("response_format = %+v, want a json_schema", params.ResponseFormat)
+	}
+	var schema struct {
+		Name   string `json:"name"`
+		Strict bool   `json:"strict"`
+		Schema struct {
+			Required []string `json:"required"`
+		} `json:"schema"`
+	}
+	if err := json.Unmarshal(params.ResponseFormat.JSONSchema, &schema); err != nil {
+		t.Fatalf("decoding schema: %v", err)
+	}
+	if schema.Name != "translation" || !schema.Strict {
+		t.Errorf("schema %q strict=%v, want the strict translation schema", schema.Name, schema.Strict)
+	}
+	if got := strings.Join(schema.Schema.Required, ","); got != "zh,pinyin,classifier,senses" {
+		t.Errorf("required properties = %s, want zh,pinyin,classifier,senses", got)
+	}
+}
+
+func TestTranslateUnauthorized(t *testing.T) {
+	var requests int
+	ai, waits := newTestAI(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		respond(http.StatusUnauthorized, `{"error": {"message": "Incorrect API key provided"}}`)(w, r)
+	})
+
+	_, err := ai.Translate("Hello")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Translate error = %v, want an APIError with status 401", err)
+	}
+	if !strings.Contains(err.Error(), "Incorrect API key") {
+		t.Errorf("error %q doesn't include the API's message", err)
+	}
+	if requests != 1 || len(*waits) != 0 {
+		t.Errorf("sent %d requests with waits %v, want a single request as 401 isn't retried", requests, *waits)
+	}
+}
+
+func TestTranslateRateLimited(t *testing.T) {
+	t.Run("retried until it succeeds", func(t *testing.T) {
+		var requests int
+		ai, waits := newTestAI(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				respond(http.StatusTooManyRequests, `{"error": {"message": "Rate limit reached"}}`)(w, r)
+				return
+			}
+			respond(http.StatusOK, completion(helloTranslation))(w, r)
+		})
+
+		if _, err := ai.Translate("Hello"); err != nil {
+			t.Fatalf("Translate: %v", err)
+		}
+		if requests != 2 || len(*waits) != 1 || (*waits)[0] != backoff(0) {
+			t.Errorf("sent %d requests with waits %v, want 2 with a single wait of %v", requests, *waits, backoff(0))
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		var requests int
+		ai, waits := newTestAI(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			respond(http.StatusTooManyRequests, `{"error": {"message": "Rate limit reached"}}`)(w, r)
+		})
+		var log bytes.Buffer
+		ai.Logger = slog.New(slog.NewTextHandler(&log, nil))
+
+		_, err := ai.Translate("Hello")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("Translate error = %v, want an APIError with status 429", err)
+		}
+		if requests != translateAttempts {
+			t.Errorf("sent %d requests, want %d", requests, translateAttempts)
+		}
+		if len(*waits) != translateAttempts-1 {
+			t.Errorf("waited %v, want a wait before each retry", *waits)
+		}
+		if retries := strings.Count(log.String(), "retrying translation"); retries != translateAttempts-1 {
+			t.Errorf("logged %d retries, want %d:\n%s", retries, translateAttempts-1, log.String())
+		}
+	})
+}
+
+func TestTranslateMalformedJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"response", `<html>Bad gateway</html>`},
+		{"message content", completion(`zh: 你好, pinyin: Nǐ hǎo`)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ai, _ := newTestAI(t, respond(http.StatusOK, test.body))
+
+			_, err := ai.Translate("Hello")
+			var syntaxErr *json.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Translate error = %v, want a JSON decoding error", err)
+			}
+		})
+	}
+}
+
+func TestTranslateEmptyResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"empty body", "", ErrEmptyResponse},
+		{"whitespace body", " \n", ErrEmptyResponse},
+		{"no choices", `{"choices": [], "usage": {"total_tokens": 5}}`, ErrNoChoices},
+		{"missing choices", `{"usage": {"total_tokens": 5}}`, ErrNoChoices},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ai, _ := newTestAI(t, respond(http.StatusOK, test.body))
+
+			if _, err := ai.Translate("Hello"); !errors.Is(err, test.want) {
+				t.Errorf("Translate error = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestTranslateTruncatedResponse(t *testing.T) {
+	full := completion(helloTranslation)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"connection closed early", func(w http.ResponseWriter, r *http.Request) {
+			// The server closes the connection once the handler returns short of the length
+			w.Header().Set("Content-Length", strconv.Itoa(len(full)))
+			io.WriteString(w, full[:len(full)/2])
+		}},
+		{"JSON cut off", respond(http.StatusOK, full[:len(full)/2])},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ai, _ := newTestAI(t, test.handler)
+
+			_, err := ai.Translate("Hello")
+			if !errors.Is(err, ErrTruncatedResponse) {
+				t.Fatalf("Translate error = %v, want ErrTruncatedResponse", err)
+			}
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				t.Errorf("Translate error = %v, want truncation rather than a JSON syntax error", err)
+			}
+		})
+	}
+}
+
+func TestTranslateRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		// the wait before the retry must be within [min, max]
+		min, max time.Duration
+	}{
+		{"seconds", "5", 5 * time.Second, 5 * time.Second},
+		{"HTTP date", time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat), 28 * time.Second, 30 * time.Second},
+		{"shorter than the backoff", "0", backoff(0), backoff(0)},
+		{"invalid", "soon", backoff(0), backoff(0)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int
+			ai, waits := newTestAI(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == 1 {
+					w.Header().Set("Retry-After", test.retryAfter)
+					respond(http.StatusTooManyRequests, `{"error": {"message": "Rate limit reached"}}`)(w, r)
+					return
+				}
+				respond(http.StatusOK, completion(helloTranslation))(w, r)
+			})
+
+			if _, err := ai.Translate("Hello"); err != nil {
+				t.Fatalf("Translate: %v", err)
+			}
+			if len(*waits) != 1 {
+				t.Fatalf("waited %v, want a single wait", *waits)
+			}
+			if wait := (*waits)[0]; wait < test.min || wait > test.max {
+				t.Errorf("waited %v, want between %v and %v", wait, test.min, test.max)
+			}
+		})
+	}
+
+	t.Run("longer than the longest wait", func(t *testing.T) {
+		var requests int
+		ai, waits := newTestAI(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Retry-After", strconv.Itoa(int(2*maxRetryWait/time.Second)))
+			respond(http.StatusTooManyRequests, `{"error": {"message": "Rate limit reached"}}`)(w, r)
+		})
+
+		_, err := ai.Translate("Hello")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.RetryAfter != 2*maxRetryWait {
+			t.Fatalf("Translate error = %v, want an APIError asking to wait %v", err, 2*maxRetryWait)
+		}
+		if requests != 1 || len(*waits) != 0 {
+			t.Errorf("sent %d requests with waits %v, want the error returned without waiting", requests, *waits)
+		}
+	})
+}
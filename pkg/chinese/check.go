@@ -0,0 +1,48 @@
+// check.go
+
+package chinese
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// CheckConnection verifies the API can be reached with the API key and that the model is
+// available to it. It looks the model up rather than requesting a completion, so it uses
+// no tokens. The error explains the likely cause of a failure.
+func (ai *AI) CheckConnection(ctx context.Context) error {
+	req, err := ai.newRequest(ctx, http.MethodGet, "/models/"+url.PathEscape(ai.Model), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := ai.do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			return fmt.Errorf("timed out reaching the API, check your network connection: %w", err)
+		}
+		return fmt.Errorf("couldn't reach the API, check your network connection: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := newAPIError(resp, body)
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("the API key was rejected, check that it is correct and not revoked: %w", apiErr)
+	case http.StatusForbidden:
+		return fmt.Errorf("the API key isn't allowed to use the API, check its organization, project and permissions: %w", apiErr)
+	case http.StatusNotFound:
+		return fmt.Errorf("model %q doesn't exist or isn't available to this API key: %w", ai.Model, apiErr)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("rate limited or out of quota, check your plan and billing: %w", apiErr)
+	}
+	return fmt.Errorf("the API returned an error: %w", apiErr)
+}
@@ -0,0 +1,90 @@
+// explain.go
+
+package chinese
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// explainPrompt is the system prompt for explanation requests
+const explainPrompt = "You are a Chinese teacher. The user is learning the Chinese translation of an English sentence. " +
+	"Explain the grammar and word choices of the Chinese, word by word where useful, and mention any " +
+	"alternative phrasings. Answer in English, in plain text without Markdown, and keep it concise."
+
+// Explain asks the AI to explain the grammar and word choices of the card's translation.
+// The explanation is streamed: onText is called with each piece of text as it arrives.
+// Cancelling the context stops the request.
+func (ai *AI) Explain(ctx context.Context, card Flashcard, onText func(string)) error {
+	question := fmt.Sprintf("English: %s\nChinese: %s\nPinyin: %s", card.English, card.Chinese, card.Pinyin)
+	if card.Gloss != "" {
+		question += "\nMeaning: " + card.Gloss
+	}
+
+	params := ChatCompletionsParams{
+		Messages: []Message{
+			{
+				Role:    "system",
+				Content: explainPrompt,
+			},
+			{
+				Role:    "user",
+				Content: question,
+			},
+		},
+		Model:           ai.Model,
+		ReasoningEffort: ai.ReasoningEffort,
+		Verbosity:       ai.Verbosity,
+		Stream:          true,
+		StreamOptions:   &StreamOptions{IncludeUsage: true},
+	}
+
+	resp, err := ai.post(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp, b)
+	}
+	return ai.readStream(resp.Body, onText)
+}
+
+// readStream reads server-sent chat completion chunks, passing their text to onText
+func (ai *AI) readStream(r io.Reader, onText func(string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			return nil
+		}
+
+		var chunk ChatCompletionsChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("decoding OpenAI API response: %w", err)
+		}
+		if chunk.Usage != nil {
+			ai.tokens.Add(int64(chunk.Usage.TotalTokens))
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				onText(choice.Delta.Content)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	// The stream ended without [DONE], e.g. the connection was closed
+	return io.ErrUnexpectedEOF
+}
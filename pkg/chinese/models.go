@@ -0,0 +1,192 @@
+// models.go
+
+package chinese
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Flashcard represents a single card in the deck
+type Flashcard struct {
+	ID      int    `json:"id"`
+	English string `json:"en"`
+	Chinese string `json:"zh"`
+	Pinyin  string `json:"pinyin"`
+	Notes   string `json:"notes,omitempty"`
+
+	// Gloss disambiguates which meaning of the English the card is about, e.g. "river bank"
+	Gloss string `json:"gloss,omitempty"`
+
+	// Classifier is the measure word of a noun, e.g. "本 (běn)", empty for other cards
+	Classifier string `json:"classifier,omitempty"`
+
+	// SpokenPinyin is the Pinyin as pronounced after tone sandhi, empty if it is the same as Pinyin
+	SpokenPinyin string `json:"spoken_pinyin,omitempty"`
+
+	// HSKLevel is the HSK level of the card's vocabulary, from 1 to 9, or 0 if unknown
+	HSKLevel int `json:"hsk,omitempty"`
+
+	// Mnemonic is an optional visual cue shown with the English, typically an emoji or two
+	Mnemonic string `json:"mnemonic,omitempty"`
+
+	// Image is an optional picture for the card, a file path or URL. The terminal can't
+	// display it, so the card view shows where it is.
+	Image string `json:"image,omitempty"`
+
+	// Source is where the English came from, e.g. the book or article it was read in
+	Source string `json:"source,omitempty"`
+
+	// Tags are free-form labels for grouping cards, e.g. "food" or "HSK textbook"
+	Tags []string `json:"tags,omitempty"`
+
+	// Pinned cards come first in every review session
+	Pinned bool `json:"pinned,omitempty"`
+
+	// AudioPath is an optional pronunciation recording, relative to the audio directory
+	AudioPath string `json:"audio,omitempty"`
+
+	// CreatedAt is when the card was added, nil for cards added before it was recorded
+	CreatedAt *time.Time `json:"created,omitempty"`
+
+	// History lists the previous translations of the card, oldest first
+	History []Revision `json:"history,omitempty"`
+}
+
+// Revision is a previous translation of a card and when it was replaced
+type Revision struct {
+	ZH       string    `json:"zh"`
+	Pinyin   string    `json:"pinyin"`
+	Replaced time.Time `json:"replaced"`
+}
+
+// RecordRevision adds the translation of the previous version of the card to its history,
+// unless the translation is unchanged
+func (c *Flashcard) RecordRevision(previous Flashcard, at time.Time) {
+	if previous.Chinese == c.Chinese && previous.Pinyin == c.Pinyin {
+		return
+	}
+	// Clipped so the new revision never overwrites a history shared with another card
+	c.History = append(slices.Clip(previous.History), Revision{
+		ZH:       previous.Chinese,
+		Pinyin:   previous.Pinyin,
+		Replaced: at,
+	})
+}
+
+// Translation represents the AI's translation of an English sentence
+type Translation struct {
+	ZH         string  `json:"zh"`
+	Pinyin     string  `json:"pinyin"`
+	Classifier string  `json:"classifier"`
+	Senses     []Sense `json:"senses"`
+
+	// SpokenPinyin is only requested when tone sandhi is enabled
+	SpokenPinyin string `json:"spoken_pinyin,omitempty"`
+}
+
+// Card creates a card for the most common meaning of the translated English
+func (t Translation) Card(english string) Flashcard {
+	card := Flashcard{
+		English:    english,
+		Chinese:    t.ZH,
+		Pinyin:     t.Pinyin,
+		Classifier: t.Classifier,
+	}
+	if t.SpokenPinyin != t.Pinyin {
+		card.SpokenPinyin = t.SpokenPinyin
+	}
+	if len(t.Senses) > 1 {
+		card.Gloss = t.Senses[0].Gloss
+	}
+	return card
+}
+
+// SenseCard creates a card for one of the meanings of the translated English
+func (t Translation) SenseCard(english string, sense Sense) Flashcard {
+	card := Flashcard{
+		English: english,
+		Chinese: sense.ZH,
+		Pinyin:  sense.Pinyin,
+		Gloss:   sense.Gloss,
+	}
+	// The measure word and spoken Pinyin were only given for the most common meaning
+	if sense.ZH == t.ZH {
+		card.Classifier = t.Classifier
+		if t.SpokenPinyin != t.Pinyin {
+			card.SpokenPinyin = t.SpokenPinyin
+		}
+	}
+	return card
+}
+
+// Example is an English sentence with the translation wanted for it, given to the AI
+// before the sentence to translate to set the style and register of translations
+type Example struct {
+	English    string `json:"en"`
+	ZH         string `json:"zh"`
+	Pinyin     string `json:"pinyin"`
+	Classifier string `json:"classifier,omitempty"`
+}
+
+// Sense is one of several meanings of an ambiguous English word
+type Sense struct {
+	ZH     string `json:"zh"`
+	Pinyin string `json:"pinyin"`
+	Gloss  string `json:"gloss"`
+}
+
+// Message represents a message to or from the AI
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ResponseFormat represents the response format for the AI API
+type ResponseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema json.RawMessage `json:"json_schema"`
+}
+
+// ChatCompletionsParams represents the parameters for the chat completions API
+type ChatCompletionsParams struct {
+	Messages            []Message       `json:"messages"`
+	Model               string          `json:"model"`
+	MaxCompletionTokens *int            `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64        `json:"temperature,omitempty"`
+	ResponseFormat      *ResponseFormat `json:"response_format,omitempty"`
+	ReasoningEffort     string          `json:"reasoning_effort,omitempty"`
+	Verbosity           string          `json:"verbosity,omitempty"`
+	Seed                *int            `json:"seed,omitempty"`
+	Stream              bool            `json:"stream,omitempty"`
+	StreamOptions       *StreamOptions  `json:"stream_options,omitempty"`
+}
+
+// StreamOptions represents the options for streamed responses
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// ChatCompletionsResult represents the result from the chat completions API
+type ChatCompletionsResult struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// ChatCompletionsChunk represents one event of a streamed chat completions response
+type ChatCompletionsChunk struct {
+	Choices []struct {
+		Delta Message `json:"delta"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+}
+
+// Usage represents the token usage reported by the API
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
@@ -0,0 +1,26 @@
+// models_test.go
+
+package chinese
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordRevisionCopiesHistory(t *testing.T) {
+	previous := Flashcard{Chinese: "你好", Pinyin: "nǐ hǎo", History: make([]Revision, 1, 4)}
+	previous.History[0] = Revision{ZH: "您好", Pinyin: "nín hǎo"}
+
+	first, second := previous, previous
+	first.Chinese = "哈喽"
+	first.RecordRevision(previous, time.Now())
+	second.Chinese = "喂"
+	second.RecordRevision(previous, time.Now())
+
+	if len(first.History) != 2 || first.History[1].ZH != "你好" {
+		t.Fatalf("first.History = %+v, want the previous translation added", first.History)
+	}
+	if &first.History[0] == &second.History[0] || &first.History[0] == &previous.History[0] {
+		t.Error("RecordRevision shares the history of the previous card")
+	}
+}
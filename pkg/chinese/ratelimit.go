@@ -0,0 +1,42 @@
+// ratelimit.go
+
+package chinese
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket spacing out API requests to a fixed rate.
+// The bucket holds a single token so requests are evenly spaced rather than bursty.
+type RateLimiter struct {
+	PerMinute int
+
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter creates a limiter allowing perMinute requests per minute
+func NewRateLimiter(perMinute int) *RateLimiter {
+	return &RateLimiter{
+		PerMinute: perMinute,
+		interval:  time.Minute / time.Duration(perMinute),
+	}
+}
+
+// Wait blocks until the next request may be sent and returns how long it waited
+func (l *RateLimiter) Wait() time.Duration {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	time.Sleep(delay)
+	return delay
+}
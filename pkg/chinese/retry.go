@@ -0,0 +1,63 @@
+// retry.go
+
+package chinese
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetryWait is the longest a request waits before being retried; if the API asks
+// for a longer wait, the error is returned instead
+const maxRetryWait = time.Minute
+
+// APIError is returned when the API responds with an error status
+type APIError struct {
+	StatusCode int
+	Body       string
+
+	// RetryAfter is how long the API asked to wait before retrying, 0 if it didn't say
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("OpenAI API returned %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// Temporary reports whether the request may succeed if retried: rate limits and server errors
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// newAPIError creates the error for a response with an error status
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+// It returns 0 if the header is missing, invalid or in the past.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+// backoff returns the wait before retrying after the given failed attempt, doubling each time
+func backoff(attempt int) time.Duration {
+	return time.Second << attempt
+}
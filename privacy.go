@@ -0,0 +1,61 @@
+// privacy.go
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// answer returns the revealed answer text, obscured in privacy mode until unmasked
+func (a *App) answer(text string) string {
+	if !a.Privacy || a.Unmasked {
+		return text
+	}
+	return mask(text)
+}
+
+// hidesChinese reports whether privacy mode obscures the Chinese, which is the answer
+// except in reverse mode, where it is the prompt
+func (a *App) hidesChinese() bool {
+	return a.Privacy && !a.Unmasked && !a.Reverse
+}
+
+// hidesPinyin reports whether privacy mode obscures the Pinyin, which is the answer
+// unless it is shown from the start, as in character practice
+func (a *App) hidesPinyin() bool {
+	_, upFront := a.RevealStyle.Shows(0)
+	return a.Privacy && !a.Unmasked && !upFront
+}
+
+// mask replaces every visible character of text with a block, keeping spaces
+func mask(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('■')
+		}
+	}
+	return b.String()
+}
+
+// TogglePrivacy turns privacy mode on or off
+func (a *App) TogglePrivacy() {
+	a.Privacy = !a.Privacy
+	a.Unmasked = false
+	if a.Privacy {
+		a.SetStatus("Privacy mode on, press v to show the answer")
+	} else {
+		a.SetStatus("Privacy mode off")
+	}
+}
+
+// ToggleUnmasked shows or hides the answer of the current card in privacy mode
+func (a *App) ToggleUnmasked() {
+	if !a.Privacy {
+		return
+	}
+	a.Unmasked = !a.Unmasked
+	a.UpdateCardView()
+}
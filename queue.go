@@ -0,0 +1,176 @@
+// queue.go
+package main
+
+import (
+	"math/rand/v2"
+	"slices"
+
+	"chinese/pkg/chinese"
+)
+
+// reviewQueue is the order in which cards are reviewed this session, as card IDs.
+// It starts with the pinned cards, then the others in deck order, and is kept in step with the deck lazily, so cards
+// added or removed by other actions need no bookkeeping.
+type reviewQueue struct {
+	ids []int
+
+	// filter limits the session to the matching cards, all cards are reviewed if nil
+	filter func(chinese.Flashcard) bool
+}
+
+// sync drops cards no longer in the deck or filtered out and queues new cards at the end
+func (q *reviewQueue) sync(deck []chinese.Flashcard) {
+	inDeck := make(map[int]bool, len(deck))
+	pinned := make(map[int]bool)
+	for _, card := range deck {
+		inDeck[card.ID] = q.filter == nil || q.filter(card)
+		pinned[card.ID] = card.Pinned
+	}
+
+	queued := make(map[int]bool, len(q.ids))
+	ids := q.ids[:0]
+	for _, id := range q.ids {
+		if inDeck[id] && !queued[id] {
+			ids = append(ids, id)
+			queued[id] = true
+		}
+	}
+	// Newly queued pinned cards go before the first queued card that isn't pinned,
+	// so a new queue starts with the pinned cards
+	firstUnpinned := slices.IndexFunc(ids, func(id int) bool {
+		return !pinned[id]
+	})
+	if firstUnpinned < 0 {
+		firstUnpinned = len(ids)
+	}
+	for _, card := range deck {
+		if !inDeck[card.ID] || queued[card.ID] {
+			continue
+		}
+		if card.Pinned {
+			ids = slices.Insert(ids, firstUnpinned, card.ID)
+			firstUnpinned++
+		} else {
+			ids = append(ids, card.ID)
+		}
+	}
+	q.ids = ids
+}
+
+// StartQueue moves to the first card of the review queue, a pinned card if there is one
+func (a *App) StartQueue() {
+	a.mutateDeck(func() error {
+		a.queue.sync(a.Deck)
+		if len(a.queue.ids) > 0 {
+			a.CurrentCardIdx = a.cardIndex(a.queue.ids[0])
+		}
+		return nil
+	})
+}
+
+// contains reports whether the card is queued
+func (q *reviewQueue) contains(id int) bool {
+	return slices.Contains(q.ids, id)
+}
+
+// next returns the card queued after the given one, wrapping around at the end.
+// A card that isn't queued is followed by the first queued card, and it stays
+// current if the queue is empty.
+func (q *reviewQueue) next(id int) int {
+	if len(q.ids) == 0 {
+		return id
+	}
+	pos := slices.Index(q.ids, id)
+	return q.ids[(pos+1)%len(q.ids)]
+}
+
+// postpone moves the given card to the end of the queue and returns the card that followed it
+func (q *reviewQueue) postpone(id int) int {
+	next := q.next(id)
+	if pos := slices.Index(q.ids, id); pos >= 0 {
+		q.ids = append(slices.Delete(q.ids, pos, pos+1), id)
+	}
+	return next
+}
+
+// moveAfter moves the given card to offset places after another card, or to the end of the queue
+func (q *reviewQueue) moveAfter(id, after, offset int) {
+	pos := slices.Index(q.ids, id)
+	if pos < 0 || id == after {
+		return
+	}
+	q.ids = slices.Delete(q.ids, pos, pos+1)
+	target := min(slices.Index(q.ids, after)+offset, len(q.ids))
+	q.ids = slices.Insert(q.ids, target, id)
+}
+
+// New card positions in the review queue
+const (
+	NewCardsEnd  = "end"
+	NewCardsNext = "next"
+	NewCardsSoon = "soon"
+)
+
+// newCardsSoonRange is how many cards at most come before a new card queued soon
+const newCardsSoonRange = 5
+
+// queueNewCard places a card just added to the deck in the review queue according
+// to the configuration. The card stays where it was added to the deck. The caller must hold a.mu.
+func (a *App) queueNewCard(id int) {
+	a.queue.sync(a.Deck)
+
+	var offset int
+	switch a.Config.NewCards {
+	case NewCardsNext:
+		offset = 1
+	case NewCardsSoon:
+		offset = 1 + rand.IntN(newCardsSoonRange)
+	default:
+		return
+	}
+	a.queue.moveAfter(id, a.Deck[a.CurrentCardIdx].ID, offset)
+}
+
+// nextCard moves to the next card in the review queue, unrevealed.
+// If skip is set, the current card is first moved to the end of the queue.
+// Once the session target is reached the session ends instead.
+func (a *App) nextCard(skip bool) {
+	if a.targetReached() {
+		a.Quit()
+		return
+	}
+	a.RevealStep = 0
+	a.Unmasked = false
+	a.Status = ""
+	a.Audio.Stop()
+	a.checkTimeLimit()
+	a.mutateDeck(func() error {
+		if len(a.Deck) == 0 {
+			return nil
+		}
+		a.queue.sync(a.Deck)
+		current := a.Deck[a.CurrentCardIdx].ID
+		next := a.queue.next(current)
+		if skip {
+			next = a.queue.postpone(current)
+		}
+		if a.order != nil {
+			next = a.order.next(a.queue.ids, current)
+		}
+		if a.mergesHomographs() {
+			// Cards merged into an earlier card with the same Chinese are shown with it
+			merged := mergedHomographs(a.Deck)
+			for i := 0; i < len(a.queue.ids) && merged[next]; i++ {
+				next = a.queue.next(next)
+			}
+		}
+		a.CurrentCardIdx = a.cardIndex(next)
+		return nil
+	})
+}
+
+// SkipCard postpones the current card to the end of the session without reviewing it
+func (a *App) SkipCard() {
+	a.nextCard(true)
+	a.SetStatus("Skipped the previous card, it will come back later in this session")
+}
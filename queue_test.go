@@ -0,0 +1,39 @@
+// queue_test.go
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"chinese/pkg/chinese"
+)
+
+func TestQueueSyncPinned(t *testing.T) {
+	deck := []chinese.Flashcard{{ID: 1}, {ID: 2, Pinned: true}, {ID: 3}}
+	var q reviewQueue
+	q.sync(deck)
+	if want := []int{2, 1, 3}; !slices.Equal(q.ids, want) {
+		t.Fatalf("new queue = %v, want %v", q.ids, want)
+	}
+
+	// Cards added later go before the first card that isn't pinned if they are pinned
+	deck = append(deck, chinese.Flashcard{ID: 4}, chinese.Flashcard{ID: 5, Pinned: true})
+	q.sync(deck)
+	if want := []int{2, 5, 1, 3, 4}; !slices.Equal(q.ids, want) {
+		t.Errorf("queue after adding cards = %v, want %v", q.ids, want)
+	}
+}
+
+func TestWeightedOrderPinnedFirst(t *testing.T) {
+	pinned := map[int]bool{3: true, 5: true}
+	o := newWeightedOrder("test", func(int) float64 { return 1 }, func(id int) bool { return pinned[id] })
+	ids := []int{3, 5, 1, 2, 4}
+
+	current := 1
+	for _, want := range []int{3, 5} {
+		current = o.next(ids, current)
+		if current != want {
+			t.Fatalf("next = %d, want pinned card %d", current, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// quiz.go
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// quizOptions is the number of choices in a multiple-choice question
+const quizOptions = 4
+
+// quizChoices returns the choices for a question about the card in random order:
+// the card itself and up to three other cards as distractors. Cards with the same
+// Chinese or English as another choice are left out, as they could be right too.
+func quizChoices(card chinese.Flashcard, deck []chinese.Flashcard) []chinese.Flashcard {
+	choices := []chinese.Flashcard{card}
+	seenChinese := map[string]bool{quizKey(card.Chinese): true}
+	seenEnglish := map[string]bool{normalizeEnglish(card.English): true}
+	for _, i := range rand.Perm(len(deck)) {
+		if len(choices) == quizOptions {
+			break
+		}
+		other := deck[i]
+		zh, en := quizKey(other.Chinese), normalizeEnglish(other.English)
+		if zh == "" || seenChinese[zh] || seenEnglish[en] {
+			continue
+		}
+		seenChinese[zh], seenEnglish[en] = true, true
+		choices = append(choices, other)
+	}
+	rand.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
+	return choices
+}
+
+// quizKey returns the Chinese text without punctuation and spacing, to compare choices
+func quizKey(zh string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return r
+		}
+		return -1
+	}, zh)
+}
+
+// ShowQuiz starts a multiple-choice review of the cards in the review order: each question
+// shows the English of a card and asks which Chinese translation is right. Answers count
+// towards the statistics. Escape returns to the card view.
+func (a *App) ShowQuiz() {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetWordWrap(true)
+	view.SetBorder(true).
+		SetTitle(" Quiz ").
+		SetTitleAlign(tview.AlignCenter)
+
+	var card chinese.Flashcard
+	var choices []chinese.Flashcard
+	var answered bool
+	theme := a.Theme
+
+	// question renders the choices, marking the right one and the chosen one once answered
+	question := func(chosen int) string {
+		var text strings.Builder
+		text.WriteString("\n\n" + theme.LabelTag("English:") + "\n")
+		text.WriteString(theme.Color(theme.English, tview.Escape(card.English)) + "\n")
+		if card.Gloss != "" {
+			text.WriteString("(" + tview.Escape(card.Gloss) + ")\n")
+		}
+		text.WriteString("\n")
+		for i, choice := range choices {
+			line := fmt.Sprintf("%d. %s", i+1, tview.Escape(choice.Chinese))
+			switch {
+			case answered && choice.ID == card.ID:
+				line = "[green]" + line + " (" + tview.Escape(choice.Pinyin) + ")[" + theme.Text + "]"
+			case answered && i == chosen:
+				line = "[red]" + line + "[" + theme.Text + "]"
+			default:
+				line = theme.Color(theme.Chinese, line)
+			}
+			text.WriteString(line + "\n")
+		}
+		if answered {
+			text.WriteString("\nPress any key for the next question, Escape to stop")
+		} else {
+			text.WriteString(fmt.Sprintf("\nPress 1-%d to answer, Escape to stop", len(choices)))
+		}
+		return text.String()
+	}
+
+	// ask shows a question about the current card, or returns false if there aren't enough cards
+	ask := func() bool {
+		var ok bool
+		if card, ok = a.currentCard(); !ok {
+			return false
+		}
+		a.readDeck(func() {
+			choices = quizChoices(card, a.Deck)
+		})
+		if len(choices) < 2 {
+			return false
+		}
+		answered = false
+		view.SetText(question(-1))
+		return true
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			a.Application.SetRoot(a.MainView, true)
+			a.UpdateCardView()
+		case answered:
+			a.nextCard(false)
+			ask()
+		case event.Rune() >= '1' && event.Rune() < '1'+rune(len(choices)):
+			chosen := int(event.Rune() - '1')
+			answered = true
+			a.RecordReview(card.ID)
+			a.RecordAnswer(card.ID, choices[chosen].ID == card.ID)
+			view.SetText(question(chosen))
+		}
+		return nil
+	})
+
+	if !ask() {
+		a.SetStatus("The quiz needs at least two cards with different translations")
+		return
+	}
+	a.Application.SetRoot(centered(view), true)
+}
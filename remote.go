@@ -0,0 +1,90 @@
+// remote.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"chinese/pkg/chinese"
+)
+
+// deckFetchTimeout bounds the time taken to download a deck
+const deckFetchTimeout = 30 * time.Second
+
+// ErrReadOnlyDeck is returned when saving a deck loaded from a URL without a local copy
+var ErrReadOnlyDeck = errors.New("the deck was loaded from a URL and is read-only, use --local-file to save changes")
+
+// isURL reports whether the deck location is an http(s) URL rather than a file
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// fetchCards downloads and reads all cards from a JSONL URL, gzip-compressed if the path ends in .gz
+func fetchCards(location string, names FieldNames) ([]chinese.Flashcard, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: deckFetchTimeout}
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("downloading deck: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading deck: %s returned %s", location, resp.Status)
+	}
+
+	cards, err := decodeCards(resp.Body, isGzip(u.Path), names)
+	if err != nil {
+		return nil, fmt.Errorf("reading deck from %s: %w", location, err)
+	}
+	return cards, nil
+}
+
+// LoadDeckURL loads flashcards from an http(s) URL. Changes are saved to the local file;
+// without one, the deck can't be changed. A local file that already exists holds the
+// changes of earlier sessions and is loaded instead of downloading the deck again.
+func (a *App) LoadDeckURL(location, localFile string) error {
+	if localFile != "" {
+		if _, err := os.Stat(localFile); err == nil {
+			if err := a.LoadDeck(localFile); err != nil {
+				return err
+			}
+			a.DeckURL = location
+			a.Logger.Info("local copy loaded instead of the URL", "file", localFile, "url", location)
+			return nil
+		}
+	}
+
+	cards, err := readCards(location, a.Config.FieldNames)
+	if err != nil {
+		return err
+	}
+	a.DeckURL = location
+	a.FlashcardsFile = localFile
+	a.Deck = append(a.Deck, cards...)
+	return nil
+}
+
+// deckName returns the name of the deck shown to the user
+func (a *App) deckName() string {
+	if a.DeckFiles != nil {
+		return a.combinedDeckName()
+	}
+	if a.DeckURL != "" {
+		if u, err := url.Parse(a.DeckURL); err == nil && path.Base(u.Path) != "/" {
+			return path.Base(u.Path)
+		}
+		return a.DeckURL
+	}
+	return filepath.Base(a.FlashcardsFile)
+}
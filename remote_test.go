@@ -0,0 +1,60 @@
+// remote_test.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"chinese/pkg/chinese"
+)
+
+func TestLoadDeckURLLocalCopy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id":1,"en":"Hello","zh":"你好","pinyin":"Nǐ hǎo"}`)
+	}))
+	defer server.Close()
+	localFile := filepath.Join(t.TempDir(), "deck.jsonl")
+
+	// The first session downloads the deck and writes it in full with the first new card
+	a := NewApp("test-key", "test-model")
+	if err := a.LoadDeckURL(server.URL+"/deck.jsonl", localFile); err != nil {
+		t.Fatalf("LoadDeckURL: %v", err)
+	}
+	if _, err := a.insertCard(chinese.Flashcard{English: "Thanks", Chinese: "谢谢", Pinyin: "Xièxie"}); err != nil {
+		t.Fatalf("insertCard: %v", err)
+	}
+	if _, err := a.insertCard(chinese.Flashcard{English: "Goodbye", Chinese: "再见", Pinyin: "Zàijiàn"}); err != nil {
+		t.Fatalf("insertCard: %v", err)
+	}
+
+	// The next session keeps the cards added to the local copy
+	a = NewApp("test-key", "test-model")
+	if err := a.LoadDeckURL(server.URL+"/deck.jsonl", localFile); err != nil {
+		t.Fatalf("LoadDeckURL: %v", err)
+	}
+	if len(a.Deck) != 3 {
+		t.Errorf("loaded %d cards, want the 3 cards of the local copy", len(a.Deck))
+	}
+}
+
+func TestLoadDeckURLReadOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id":1,"en":"Hello","zh":"你好","pinyin":"Nǐ hǎo"}`)
+	}))
+	defer server.Close()
+
+	a := NewApp("test-key", "test-model")
+	if err := a.LoadDeckURL(server.URL+"/deck.jsonl", ""); err != nil {
+		t.Fatalf("LoadDeckURL: %v", err)
+	}
+	if err := a.DeleteCard(1); !errors.Is(err, ErrReadOnlyDeck) {
+		t.Errorf("DeleteCard error = %v, want ErrReadOnlyDeck", err)
+	}
+	if len(a.Deck) != 1 || a.IsDirty() {
+		t.Errorf("the read-only deck was changed: %d cards, dirty %v", len(a.Deck), a.IsDirty())
+	}
+}
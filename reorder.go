@@ -0,0 +1,130 @@
+// reorder.go
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// ShowCardList lists the cards in deck order. J and K move the selected card down and up,
+// I sorts the deck by ID, Enter jumps to the selected card and Escape closes the list.
+func (a *App) ShowCardList() {
+	list := tview.NewList().
+		ShowSecondaryText(false)
+
+	populate := func(selected int) {
+		list.Clear()
+		a.readDeck(func() {
+			for _, card := range a.Deck {
+				list.AddItem(fmt.Sprintf("%d: %s", card.ID, tview.Escape(card.English)), "", 0, nil)
+			}
+		})
+		list.SetCurrentItem(selected)
+	}
+	move := func(offset int) {
+		from := list.GetCurrentItem()
+		to, err := a.MoveCard(from, offset)
+		if err != nil {
+			a.Status = a.errorStatus("Error saving deck", err)
+		}
+		populate(to)
+	}
+
+	list.SetSelectedFunc(func(i int, _, _ string, _ rune) {
+		a.jumpTo(i)
+		a.Application.SetRoot(a.MainView, true)
+		a.UpdateCardView()
+	})
+	list.SetDoneFunc(func() {
+		a.Application.SetRoot(a.MainView, true)
+		a.UpdateCardView()
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'K':
+			move(-1)
+		case 'J':
+			move(1)
+		case 'I':
+			if err := a.SortByID(); err != nil {
+				a.Status = a.errorStatus("Error saving deck", err)
+			}
+			populate(0)
+		default:
+			return event
+		}
+		return nil
+	})
+
+	var current int
+	a.readDeck(func() {
+		current = a.CurrentCardIdx
+	})
+	populate(current)
+
+	list.SetBorder(true).
+		SetTitle(" Cards (J/K: move down/up, I: sort by ID) ").
+		SetTitleAlign(tview.AlignCenter)
+
+	a.Application.SetRoot(centered(list), true)
+}
+
+// MoveCard moves the card at the given deck index by offset places, keeping its ID, and
+// rewrites the file. It returns the new index of the card.
+func (a *App) MoveCard(idx, offset int) (int, error) {
+	to := idx
+	err := a.mutateDeck(func() error {
+		if err := a.checkWritable(); err != nil {
+			return err
+		}
+		if idx < 0 || idx >= len(a.Deck) {
+			return nil
+		}
+		to = min(max(idx+offset, 0), len(a.Deck)-1)
+		if to == idx {
+			return nil
+		}
+		a.snapshot()
+		current := a.Deck[a.CurrentCardIdx].ID
+		card := a.Deck[idx]
+		a.Deck = slices.Insert(slices.Delete(a.Deck, idx, idx+1), to, card)
+		a.reordered(current)
+		return a.rewriteDeck()
+	})
+	return to, err
+}
+
+// SortByID restores the deck to the order of the card IDs and rewrites the file
+func (a *App) SortByID() error {
+	return a.mutateDeck(func() error {
+		if err := a.checkWritable(); err != nil {
+			return err
+		}
+		if len(a.Deck) == 0 || slices.IsSortedFunc(a.Deck, compareIDs) {
+			return nil
+		}
+		a.snapshot()
+		current := a.Deck[a.CurrentCardIdx].ID
+		slices.SortStableFunc(a.Deck, compareIDs)
+		a.reordered(current)
+		return a.rewriteDeck()
+	})
+}
+
+// compareIDs orders cards by ID
+func compareIDs(x, y chinese.Flashcard) int {
+	return x.ID - y.ID
+}
+
+// reordered keeps the current card, given by ID, and restarts the review queue in the
+// new deck order. The caller must hold a.mu.
+func (a *App) reordered(current int) {
+	a.CurrentCardIdx = a.cardIndex(current)
+	a.queue.ids = nil
+	a.queue.sync(a.Deck)
+}
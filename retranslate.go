@@ -0,0 +1,112 @@
+// retranslate.go
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"chinese/pkg/chinese"
+)
+
+// retranslateFilters select which cards are re-translated
+var retranslateFilters = map[string]func(chinese.Flashcard) bool{
+	"all": func(chinese.Flashcard) bool {
+		return true
+	},
+	"empty": func(card chinese.Flashcard) bool {
+		return strings.TrimSpace(card.Chinese) == "" || strings.TrimSpace(card.Pinyin) == ""
+	},
+	"invalid": func(card chinese.Flashcard) bool {
+		return chinese.ValidateTranslation(card.Chinese, card.Pinyin) != nil
+	},
+	"syllables": func(card chinese.Flashcard) bool {
+		_, _, mismatched := syllableMismatch(card)
+		return mismatched
+	},
+}
+
+// Retranslate re-runs the translation of every card matching the filter, after confirmation.
+// Progress is written to out and the deck is rewritten once at the end.
+func (a *App) Retranslate(filter string, in io.Reader, out io.Writer) error {
+	match, ok := retranslateFilters[filter]
+	if !ok {
+		return fmt.Errorf("unknown filter %q (expected all, empty, invalid or syllables)", filter)
+	}
+
+	var cards []chinese.Flashcard
+	var err error
+	a.readDeck(func() {
+		err = a.checkWritable()
+		for _, card := range a.Deck {
+			if match(card) {
+				cards = append(cards, card)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		fmt.Fprintln(out, "No cards to re-translate")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Re-translate %d cards? [y/N] ", len(cards))
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		fmt.Fprintln(out, "Cancelled")
+		return nil
+	}
+	if a.AI.Limiter != nil {
+		fmt.Fprintf(out, "Limited to %d requests per minute\n", a.AI.Limiter.PerMinute)
+	}
+
+	var changed, unchanged, failed int
+	updated := make(map[int]chinese.Flashcard)
+	for i, card := range cards {
+		fmt.Fprintf(out, "[%d/%d] %s\n", i+1, len(cards), card.English)
+
+		translation, err := a.AI.Translate(card.English)
+		if err != nil {
+			fmt.Fprintf(out, "  error: %v\n", err)
+			failed++
+			continue
+		}
+		fresh := translation.Card(card.English)
+		if fresh.Chinese == card.Chinese && fresh.Pinyin == card.Pinyin && fresh.Classifier == card.Classifier &&
+			fresh.SpokenPinyin == card.SpokenPinyin {
+			unchanged++
+			continue
+		}
+
+		fmt.Fprintf(out, "  %s -> %s\n  %s -> %s\n", card.Chinese, translation.ZH, card.Pinyin, translation.Pinyin)
+		previous := card
+		card.Chinese, card.Pinyin, card.Classifier = fresh.Chinese, fresh.Pinyin, fresh.Classifier
+		card.SpokenPinyin = fresh.SpokenPinyin
+		if a.Config.KeepHistory {
+			card.RecordRevision(previous, time.Now())
+		}
+		updated[card.ID] = card
+		changed++
+	}
+
+	if changed > 0 {
+		err := a.mutateDeck(func() error {
+			for i, card := range a.Deck {
+				if u, ok := updated[card.ID]; ok {
+					a.Deck[i] = u
+				}
+			}
+			return a.rewriteDeck()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Changed %d, unchanged %d, failed %d\n", changed, unchanged, failed)
+	return nil
+}
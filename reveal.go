@@ -0,0 +1,63 @@
+// reveal.go
+package main
+
+import "fmt"
+
+// RevealStyle controls how the answer of a card is revealed
+type RevealStyle int
+
+const (
+	// RevealAll shows the Chinese and Pinyin together
+	RevealAll RevealStyle = iota
+	// RevealStaged shows the Pinyin first, then the Chinese characters
+	RevealStaged
+	// RevealCharacters shows the Pinyin from the start and only hides the Chinese characters
+	RevealCharacters
+)
+
+// ParseRevealStyle returns the reveal style with the given name
+func ParseRevealStyle(name string) (RevealStyle, error) {
+	switch name {
+	case "", "all":
+		return RevealAll, nil
+	case "staged":
+		return RevealStaged, nil
+	case "characters":
+		return RevealCharacters, nil
+	}
+	return RevealAll, fmt.Errorf("unknown reveal style %q", name)
+}
+
+// Steps returns how many reveal steps there are before moving on to the next card
+func (s RevealStyle) Steps() int {
+	if s == RevealStaged {
+		return 2
+	}
+	return 1
+}
+
+// Shows reports which answer fields are visible after the given number of reveal steps
+func (s RevealStyle) Shows(step int) (chinese, pinyin bool) {
+	switch s {
+	case RevealStaged:
+		return step >= 2, step >= 1
+	case RevealCharacters:
+		return step >= 1, true
+	}
+	return step >= 1, step >= 1
+}
+
+// ToggleHideCharacters switches between the configured reveal style and showing the
+// Pinyin from the start, so only the Chinese characters are left to recall
+func (a *App) ToggleHideCharacters() {
+	a.RevealStep = 0
+	a.Unmasked = false
+	if a.RevealStyle == RevealCharacters {
+		a.RevealStyle = a.previousReveal
+		a.SetStatus("Showing the Pinyin with the answer again")
+		return
+	}
+	a.previousReveal = a.RevealStyle
+	a.RevealStyle = RevealCharacters
+	a.SetStatus("Character practice on, the Pinyin is shown and only the characters are hidden")
+}
@@ -0,0 +1,115 @@
+// reverse.go
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"chinese/pkg/chinese"
+)
+
+// How reverse mode shows cards with the same Chinese
+const (
+	// HomographsMerge shows the English of all of them on a single card
+	HomographsMerge = "merge"
+	// HomographsSeparate reviews each card on its own
+	HomographsSeparate = "separate"
+)
+
+// ToggleReverse switches between recalling the Chinese of the English and the
+// English of the Chinese
+func (a *App) ToggleReverse() {
+	a.Reverse = !a.Reverse
+	a.RevealStep = 0
+	a.Unmasked = false
+	if a.Reverse {
+		a.SetStatus("Reverse mode on, recall the English of the Chinese")
+	} else {
+		a.SetStatus("Reverse mode off")
+	}
+}
+
+// mergesHomographs reports whether cards with the same Chinese are shown as one card
+func (a *App) mergesHomographs() bool {
+	return a.Reverse && a.Config.Homographs != HomographsSeparate
+}
+
+// cardViewFields returns the fields of the card view in order, with the Chinese first
+// in reverse mode
+func (a *App) cardViewFields() []string {
+	fields := a.Config.CardViewFields()
+	if !a.Reverse || !slices.Contains(fields, "chinese") {
+		return fields
+	}
+	reversed := []string{"chinese"}
+	for _, field := range fields {
+		if field != "chinese" {
+			reversed = append(reversed, field)
+		}
+	}
+	return reversed
+}
+
+// homographKey returns the Chinese text in the form used to compare cards, or "" if
+// the card has none. Surrounding spaces and trailing punctuation are ignored.
+func homographKey(text string) string {
+	return strings.TrimRightFunc(strings.TrimSpace(text), func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+}
+
+// homographs returns the cards with the same Chinese as another card, grouped by
+// their Chinese, with groups and cards in deck order
+func homographs(deck []chinese.Flashcard) [][]chinese.Flashcard {
+	groups := make(map[string][]chinese.Flashcard)
+	var keys []string
+	for _, card := range deck {
+		key := homographKey(card.Chinese)
+		if key == "" {
+			continue
+		}
+		if groups[key] == nil {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], card)
+	}
+
+	var found [][]chinese.Flashcard
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			found = append(found, groups[key])
+		}
+	}
+	return found
+}
+
+// mergedHomographs returns the IDs of the cards that are shown merged into an earlier
+// card with the same Chinese
+func mergedHomographs(deck []chinese.Flashcard) map[int]bool {
+	merged := make(map[int]bool)
+	for _, group := range homographs(deck) {
+		for _, card := range group[1:] {
+			merged[card.ID] = true
+		}
+	}
+	return merged
+}
+
+// homographsOf returns the cards with the same Chinese as the given one, in deck order,
+// including the card itself
+func (a *App) homographsOf(card chinese.Flashcard) []chinese.Flashcard {
+	key := homographKey(card.Chinese)
+	if key == "" {
+		return []chinese.Flashcard{card}
+	}
+	var cards []chinese.Flashcard
+	a.readDeck(func() {
+		for _, other := range a.Deck {
+			if homographKey(other.Chinese) == key {
+				cards = append(cards, other)
+			}
+		}
+	})
+	return cards
+}
@@ -0,0 +1,88 @@
+// schema.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"chinese/pkg/chinese"
+)
+
+// deckVersion is the version of the flashcards file format written by this program.
+// Files without a header line are version 1.
+const deckVersion = 2
+
+// deckHeader is the optional first line of a flashcards file, e.g. {"_meta":{"version":2}}
+type deckHeader struct {
+	Meta *deckMeta `json:"_meta"`
+}
+
+// deckMeta describes the format of a flashcards file
+type deckMeta struct {
+	Version int `json:"version"`
+}
+
+// migrations upgrade a card stored as a JSON object from one file version to the next:
+// migrations[0] upgrades version 1 to 2, migrations[1] version 2 to 3 and so on.
+// To change the format, bump deckVersion and append a migration renaming or defaulting
+// the affected fields, so older files keep loading. A version that leaves cards
+// unchanged has a nil migration, so cards needing none are decoded directly.
+var migrations = []func(card map[string]any){
+	// Version 2 only adds the header line, cards are unchanged
+	nil,
+}
+
+// parseDeckHeader returns the file version if the line is a header
+func parseDeckHeader(line json.RawMessage) (int, bool, error) {
+	var header deckHeader
+	if err := json.Unmarshal(line, &header); err != nil || header.Meta == nil {
+		return 0, false, nil
+	}
+	if header.Meta.Version < 1 || header.Meta.Version > deckVersion {
+		return 0, false, fmt.Errorf("unsupported flashcards file version %d, this program reads versions 1 to %d",
+			header.Meta.Version, deckVersion)
+	}
+	return header.Meta.Version, true, nil
+}
+
+// decodeCard decodes a card stored in a file of the given version, renaming custom
+// field names and migrating it to the current format
+func decodeCard(line json.RawMessage, version int, names FieldNames) (chinese.Flashcard, error) {
+	var card chinese.Flashcard
+	if !needsMigration(version) && !names.renames() {
+		err := json.Unmarshal(line, &card)
+		return card, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return card, err
+	}
+	names.remap(fields)
+	for _, migrate := range migrations[version-1:] {
+		if migrate != nil {
+			migrate(fields)
+		}
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return card, err
+	}
+	err = json.Unmarshal(b, &card)
+	return card, err
+}
+
+// needsMigration reports whether cards stored in a file of the given version are changed
+// by migrating them to the current format
+func needsMigration(version int) bool {
+	return slices.ContainsFunc(migrations[version-1:], func(migrate func(map[string]any)) bool {
+		return migrate != nil
+	})
+}
+
+// headerLine returns the header line written at the start of every flashcards file
+func headerLine() []byte {
+	b, _ := json.Marshal(deckHeader{Meta: &deckMeta{Version: deckVersion}})
+	return append(b, '\n')
+}
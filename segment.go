@@ -0,0 +1,144 @@
+// segment.go
+package main
+
+import (
+	_ "embed"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/tview"
+)
+
+// bundledWords is a starter list of common words for segmentation
+//
+//go:embed words.txt
+var bundledWords string
+
+// Segmenter splits Chinese text into words by matching the longest known word at each
+// position. Characters that don't start a known word are words of their own.
+type Segmenter struct {
+	words map[string]bool
+	// longest is the length of the longest known word, in characters
+	longest int
+}
+
+// segment is a piece of segmented text: a word, a number, a run of Latin letters,
+// or a single punctuation or space character
+type segment struct {
+	Text string
+	Word bool
+}
+
+// NewSegmenter creates a segmenter knowing the bundled words
+func NewSegmenter() *Segmenter {
+	s := &Segmenter{words: make(map[string]bool)}
+	s.AddWords(bundledWords)
+	return s
+}
+
+// AddWords adds a list of words, one per line. Lines starting with # are ignored.
+func (s *Segmenter) AddWords(list string) {
+	for _, line := range strings.Split(list, "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		s.words[word] = true
+		s.longest = max(s.longest, len([]rune(word)))
+	}
+}
+
+// Segment splits the text into words. Numbers and runs of Latin letters are kept whole,
+// and punctuation and spaces are returned as segments that aren't words.
+func (s *Segmenter) Segment(text string) []segment {
+	var segments []segment
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.Is(unicode.Han, r):
+			n := s.match(runes[i:])
+			segments = append(segments, segment{Text: string(runes[i : i+n]), Word: true})
+			i += n
+		case unicode.IsDigit(r), unicode.IsLetter(r):
+			// Numbers may contain a decimal point, e.g. 3.5
+			j := i + 1
+			for j < len(runes) && (sameKind(r, runes[j]) || unicode.IsDigit(r) && runes[j] == '.' && j+1 < len(runes) && unicode.IsDigit(runes[j+1])) {
+				j++
+			}
+			segments = append(segments, segment{Text: string(runes[i:j]), Word: true})
+			i = j
+		default:
+			segments = append(segments, segment{Text: string(r)})
+			i++
+		}
+	}
+	return segments
+}
+
+// match returns the length of the longest known word at the start of the characters, at least 1
+func (s *Segmenter) match(runes []rune) int {
+	for n := min(s.longest, len(runes)); n > 1; n-- {
+		if s.words[string(runes[:n])] {
+			return n
+		}
+	}
+	return 1
+}
+
+// sameKind reports whether two characters belong to the same number or run of Latin letters
+func sameKind(first, r rune) bool {
+	if unicode.IsDigit(first) {
+		return unicode.IsDigit(r)
+	}
+	return unicode.IsLetter(r) && !unicode.Is(unicode.Han, r)
+}
+
+// LoadSegmenter sets up word segmentation of the Chinese if it is enabled in the config,
+// adding the words of the configured word file to the bundled ones
+func (a *App) LoadSegmenter() error {
+	if !a.Config.SegmentWords {
+		return nil
+	}
+	segmenter := NewSegmenter()
+	if a.Config.WordFile != "" {
+		b, err := os.ReadFile(a.Config.WordFile)
+		if err != nil {
+			return err
+		}
+		segmenter.AddWords(string(b))
+	}
+	a.segmenter = segmenter
+	return nil
+}
+
+// chineseText renders revealed Chinese. With word segmentation every other word is
+// underlined, so the boundaries between words can be seen.
+func (a *App) chineseText(text string) string {
+	if a.hidesChinese() {
+		return a.Theme.Color(a.Theme.Chinese, tview.Escape(mask(text)))
+	}
+	if a.segmenter == nil {
+		return a.Theme.Color(a.Theme.Chinese, tview.Escape(text))
+	}
+
+	var b strings.Builder
+	b.WriteString("[" + a.Theme.Chinese + "]")
+	underline := false
+	for _, seg := range a.segmenter.Segment(text) {
+		switch {
+		case !seg.Word:
+			b.WriteString(tview.Escape(seg.Text))
+		case underline:
+			b.WriteString("[::u]" + tview.Escape(seg.Text) + "[::-]")
+		default:
+			b.WriteString(tview.Escape(seg.Text))
+		}
+		if seg.Word {
+			underline = !underline
+		}
+	}
+	b.WriteString("[" + a.Theme.Text + "]")
+	return b.String()
+}
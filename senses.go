@@ -0,0 +1,46 @@
+// senses.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// ShowSensePicker lets the user choose which meaning of ambiguous English to add,
+// or add one card per meaning
+func (a *App) ShowSensePicker(englishText, source string, translation chinese.Translation) {
+	list := tview.NewList()
+	for _, sense := range translation.Senses {
+		list.AddItem(tview.Escape(fmt.Sprintf("%s (%s)", sense.ZH, sense.Pinyin)), tview.Escape(sense.Gloss), 0, nil)
+	}
+	list.AddItem("All of them", "Add one card per meaning", 0, nil)
+	list.SetSelectedFunc(func(i int, _, _ string, _ rune) {
+		senses := translation.Senses
+		if i < len(senses) {
+			senses = senses[i : i+1]
+		}
+		for _, sense := range senses {
+			card := translation.SenseCard(englishText, sense)
+			card.Source = source
+			if _, err := a.insertCard(card); err != nil {
+				a.Application.SetRoot(a.MainView, true)
+				a.SetStatus(a.errorStatus("Error adding card", err))
+				return
+			}
+		}
+		a.Application.SetRoot(a.MainView, true)
+		a.UpdateCardView()
+	})
+	list.SetDoneFunc(func() {
+		a.Application.SetRoot(a.MainView, true)
+	})
+
+	list.SetBorder(true).
+		SetTitle(" Which meaning of \"" + tview.Escape(englishText) + "\"? ").
+		SetTitleAlign(tview.AlignCenter)
+
+	a.Application.SetRoot(centered(list), true)
+}
@@ -0,0 +1,137 @@
+// server.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chinese/pkg/chinese"
+)
+
+// Serve exposes the deck as a JSON API on the given address
+func (a *App) Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /cards", a.handleListCards)
+	mux.HandleFunc("GET /cards/{id}", a.handleGetCard)
+	mux.HandleFunc("POST /cards", a.handleCreateCard)
+	mux.HandleFunc("DELETE /cards/{id}", a.handleDeleteCard)
+
+	fmt.Printf("Serving %s on %s\n", a.FlashcardsFile, addr)
+	if a.AI.Limiter != nil {
+		fmt.Printf("Translations limited to %d requests per minute\n", a.AI.Limiter.PerMinute)
+	}
+	return http.ListenAndServe(addr, mux)
+}
+
+func (a *App) handleListCards(w http.ResponseWriter, r *http.Request) {
+	var cards []chinese.Flashcard
+	a.readDeck(func() {
+		cards = make([]chinese.Flashcard, len(a.Deck))
+		copy(cards, a.Deck)
+	})
+
+	writeJSON(w, http.StatusOK, cards)
+}
+
+func (a *App) handleGetCard(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid card ID")
+		return
+	}
+
+	var card chinese.Flashcard
+	var idx int
+	a.readDeck(func() {
+		idx = a.cardIndex(id)
+		if idx >= 0 {
+			card = a.Deck[idx]
+		}
+	})
+
+	if idx < 0 {
+		writeError(w, http.StatusNotFound, ErrCardNotFound.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, card)
+}
+
+func (a *App) handleCreateCard(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		English string `json:"en"`
+		Source  string `json:"source"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	input.English = strings.TrimSpace(input.English)
+	if input.English == "" {
+		writeError(w, http.StatusBadRequest, "missing English text")
+		return
+	}
+
+	if a.findEnglish(input.English) >= 0 {
+		writeError(w, http.StatusConflict, "a card with the same English is already in the deck")
+		return
+	}
+	// Cards that can't be saved are refused before spending a translation on them
+	var err error
+	a.readDeck(func() {
+		err = a.checkCanAdd()
+	})
+	if err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	card, err := a.AddCard(input.English, strings.TrimSpace(input.Source))
+	if err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, card)
+}
+
+func (a *App) handleDeleteCard(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid card ID")
+		return
+	}
+
+	if err := a.DeleteCard(id); err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// errorStatus returns the HTTP status code for an error changing the deck
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrCardNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrReadOnlyDeck), errors.Is(err, ErrCombinedDeck):
+		return http.StatusConflict
+	case errors.Is(err, ErrTranslationFailed):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// writeJSON writes v as the JSON response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,63 @@
+// server_test.go
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"chinese/pkg/chinese"
+)
+
+func TestCreateCardErrors(t *testing.T) {
+	var requests atomic.Int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, `{"error":{"message":"invalid API key"}}`, http.StatusUnauthorized)
+	}))
+	defer api.Close()
+
+	tests := []struct {
+		name string
+		// setup prepares the deck the card is added to
+		setup        func(a *App)
+		body         string
+		wantStatus   int
+		wantRequests int32
+	}{
+		{"read-only deck", func(a *App) {
+			a.DeckURL = "https://example.com/deck.jsonl"
+		}, `{"en":"Thanks"}`, http.StatusConflict, 0},
+		{"duplicate", func(a *App) {
+			a.FlashcardsFile = filepath.Join(t.TempDir(), "deck.jsonl")
+		}, `{"en":" hello "}`, http.StatusConflict, 0},
+		{"translation failure", func(a *App) {
+			a.FlashcardsFile = filepath.Join(t.TempDir(), "deck.jsonl")
+		}, `{"en":"Thanks"}`, http.StatusBadGateway, 1},
+		{"invalid body", func(a *App) {}, `{"en":`, http.StatusBadRequest, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests.Store(0)
+			a := NewApp("test-key", "test-model")
+			a.AI.BaseURL = api.URL
+			a.Deck = []chinese.Flashcard{{ID: 1, English: "Hello", Chinese: "你好", Pinyin: "Nǐ hǎo"}}
+			test.setup(a)
+
+			w := httptest.NewRecorder()
+			a.handleCreateCard(w, httptest.NewRequest(http.MethodPost, "/cards", strings.NewReader(test.body)))
+			if w.Code != test.wantStatus {
+				t.Errorf("status = %d (%s), want %d", w.Code, strings.TrimSpace(w.Body.String()), test.wantStatus)
+			}
+			if got := requests.Load(); got != test.wantRequests {
+				t.Errorf("%d translation requests, want %d", got, test.wantRequests)
+			}
+			if len(a.Deck) != 1 {
+				t.Errorf("the deck has %d cards, want it unchanged", len(a.Deck))
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// session.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// session counts what was done since the program started, for the summary printed on exit
+type session struct {
+	start    time.Time
+	reviewed int
+	// added is guarded by mu, as cards may be added in the background
+	added int
+
+	// breakDue is set when the session time limit has passed, until the reminder is dismissed
+	breakDue  bool
+	dismissed bool
+}
+
+// checkTimeLimit shows the break reminder once the configured session time limit has passed.
+// It is checked when moving to the next card rather than on a timer, so the reminder
+// never appears in the middle of a card.
+func (a *App) checkTimeLimit() {
+	limit := time.Duration(a.Config.SessionMinutes) * time.Minute
+	if limit > 0 && !a.session.dismissed && time.Since(a.session.start) >= limit {
+		a.session.breakDue = true
+	}
+}
+
+// targetReached reports whether as many cards as the session target have been reviewed
+func (a *App) targetReached() bool {
+	return a.TargetCount > 0 && a.session.reviewed >= a.TargetCount
+}
+
+// targetProgress returns how many cards are left to reach the session target, for the
+// card view header, or "" if there is no target
+func (a *App) targetProgress() string {
+	if a.TargetCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d left", max(a.TargetCount-a.session.reviewed, 0), a.TargetCount)
+}
+
+// breakReminder returns the reminder shown in the card view, or "" if no break is due
+func (a *App) breakReminder() string {
+	if !a.session.breakDue {
+		return ""
+	}
+	return fmt.Sprintf("You've studied for %d minutes, time for a break? q ends the session, Esc keeps going",
+		a.Config.SessionMinutes)
+}
+
+// DismissBreak hides the break reminder for the rest of the session
+func (a *App) DismissBreak() {
+	if !a.session.breakDue {
+		return
+	}
+	a.session.breakDue = false
+	a.session.dismissed = true
+	a.UpdateCardView()
+}
+
+// handleSignals stops the application on SIGINT or SIGTERM so Run returns and the
+// deck is flushed as on a normal quit. Ctrl-C in the terminal is handled by tview itself.
+func (a *App) handleSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		// Stop is safe to call from any goroutine, unlike the UI updates done on quit
+		a.Application.Stop()
+	}()
+}
+
+// saveUnsaved writes the deck if changes failed to be saved, reporting the outcome
+func (a *App) saveUnsaved(out io.Writer) {
+	if !a.IsDirty() {
+		return
+	}
+	if err := a.SaveDeck(); err != nil {
+		a.Logger.Error("Error saving deck", "error", err)
+		fmt.Fprintf(out, "Error saving deck: %v\n", err)
+	} else {
+		fmt.Fprintf(out, "Saved unsaved changes to %s\n", a.FlashcardsFile)
+	}
+}
+
+// Shutdown stops background activity, writes any unsaved changes to the deck and
+// prints a summary of the session. It is called once the UI has stopped.
+func (a *App) Shutdown(out io.Writer) {
+	a.StopAutoplay()
+	a.Audio.Stop()
+	a.saveUnsaved(out)
+
+	var added int
+	a.readDeck(func() {
+		added = a.session.added
+	})
+	elapsed := time.Since(a.session.start).Round(time.Second)
+	fmt.Fprintf(out, "Session: %d reviewed, %d added in %s\n", a.session.reviewed, added, elapsed)
+	if a.targetReached() {
+		fmt.Fprintf(out, "Reached your target of %d cards\n", a.TargetCount)
+	}
+	if banner := a.streakBanner(); banner != "" {
+		fmt.Fprintln(out, banner)
+	}
+}
@@ -0,0 +1,132 @@
+// split.go
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// ShowSplitCardDialog displays a form to split the current card into one card per line
+func (a *App) ShowSplitCardDialog() {
+	card, ok := a.currentCard()
+	if !ok {
+		return
+	}
+
+	form := tview.NewForm()
+	parts := tview.NewTextArea().
+		SetLabel("Parts").
+		SetText(strings.Join(strings.Fields(card.English), "\n"), false).
+		SetSize(6, 50)
+	form.AddFormItem(parts)
+	form.AddCheckbox("Keep original", false, nil)
+	form.AddButton("Split", func() {
+		keep := form.GetFormItemByLabel("Keep original").(*tview.Checkbox).IsChecked()
+		a.splitCard(card, splitLines(parts.GetText()), keep)
+	})
+	form.AddButton("Cancel", func() {
+		a.Application.SetRoot(a.MainView, true)
+	})
+
+	form.SetBorder(true).
+		SetTitle(" Split Card ").
+		SetTitleAlign(tview.AlignCenter)
+
+	a.Application.SetRoot(centered(form), true)
+}
+
+// splitCard translates the parts in the background and then applies the split
+func (a *App) splitCard(card chinese.Flashcard, parts []string, keepOriginal bool) {
+	if len(parts) == 0 {
+		a.Application.SetRoot(a.MainView, true)
+		return
+	}
+
+	progress := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("\nTranslating %d parts...", len(parts)))
+	progress.SetBorder(true).
+		SetTitle(" Split Card ").
+		SetTitleAlign(tview.AlignCenter)
+	a.Application.SetRoot(centered(progress), true)
+
+	go func() {
+		defer a.recoverPanic()
+		err := a.SplitCard(card.ID, parts, keepOriginal)
+		a.Application.QueueUpdateDraw(func() {
+			a.Application.SetRoot(a.MainView, true)
+			if err != nil {
+				a.SetStatus(a.errorStatus("Error splitting card", err))
+				return
+			}
+			a.RevealStep = 0
+			a.SetStatus(fmt.Sprintf("Split into %d cards", len(parts)))
+		})
+	}()
+}
+
+// SplitCard replaces the card with one new card per part, inserted where the card was.
+// All parts are translated before the deck is touched, so either every new card is
+// added or none is. The original card is removed unless keepOriginal is set.
+func (a *App) SplitCard(id int, parts []string, keepOriginal bool) error {
+	var err error
+	a.readDeck(func() {
+		err = a.checkWritable()
+	})
+	if err != nil {
+		return err
+	}
+
+	translations := make([]chinese.Translation, len(parts))
+	for i, part := range parts {
+		translation, err := a.AI.Translate(part)
+		if err != nil {
+			return fmt.Errorf("translating %q: %w", part, err)
+		}
+		translations[i] = translation
+	}
+
+	return a.mutateDeck(func() error {
+		if err := a.checkWritable(); err != nil {
+			return err
+		}
+		idx := a.cardIndex(id)
+		if idx < 0 {
+			return ErrCardNotFound
+		}
+		a.snapshot()
+
+		nextID := a.nextID()
+		now := time.Now().Truncate(time.Second)
+		newCards := make([]chinese.Flashcard, len(parts))
+		for i, part := range parts {
+			newCards[i] = translations[i].Card(part)
+			newCards[i].ID = nextID + i
+			newCards[i].CreatedAt = &now
+			// The parts come from the same place as the whole
+			newCards[i].Source = a.Deck[idx].Source
+			newCards[i].Tags = a.Deck[idx].Tags
+		}
+
+		insertAt := idx
+		if keepOriginal {
+			insertAt = idx + 1
+		}
+		deck := make([]chinese.Flashcard, 0, len(a.Deck)+len(newCards))
+		deck = append(deck, a.Deck[:idx]...)
+		if keepOriginal {
+			deck = append(deck, a.Deck[idx])
+		}
+		deck = append(deck, newCards...)
+		deck = append(deck, a.Deck[idx+1:]...)
+
+		a.Deck = deck
+		a.CurrentCardIdx = insertAt
+		return a.rewriteDeck()
+	})
+}
@@ -0,0 +1,164 @@
+// state.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+// dateLayout is the format of the dates recorded in the state file
+const dateLayout = "2006-01-02"
+
+// State holds study progress persisted between sessions
+type State struct {
+	// StudyDays maps a local date to the number of cards reviewed that day
+	StudyDays map[string]int `json:"study_days"`
+
+	// Cards maps card IDs to how well and when they were last reviewed
+	Cards map[int]*CardStats `json:"cards,omitempty"`
+}
+
+// CardStats counts the answers given for a card and records when it was last reviewed
+type CardStats struct {
+	Correct   int `json:"correct"`
+	Incorrect int `json:"incorrect"`
+
+	// LastIncorrect is when the card was last answered wrongly, nil if never
+	LastIncorrect *time.Time `json:"last_incorrect,omitempty"`
+
+	// LastReviewed is when the card was last reviewed, nil if it wasn't since this was recorded
+	LastReviewed *time.Time `json:"last_reviewed,omitempty"`
+
+	// Ratings lists the confidence ratings given for the card, oldest first
+	Ratings []Rating `json:"ratings,omitempty"`
+}
+
+// Accuracy returns the fraction of correct answers, or 0 if the card was never answered
+func (c CardStats) Accuracy() float64 {
+	if c.Correct+c.Incorrect == 0 {
+		return 0
+	}
+	return float64(c.Correct) / float64(c.Correct+c.Incorrect)
+}
+
+// LoadState reads the state file, returning an empty state if it does not exist
+func LoadState(filename string) (*State, error) {
+	state := &State{StudyDays: make(map[string]int)}
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, err
+	}
+	if state.StudyDays == nil {
+		state.StudyDays = make(map[string]int)
+	}
+	return state, nil
+}
+
+// Save writes the state to a file
+func (s *State) Save(filename string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(b, '\n'), 0644)
+}
+
+// RecordReview counts a review of a card on the local date of t
+func (s *State) RecordReview(id int, t time.Time) {
+	s.RecordStudy(t)
+	s.cardStats(id).LastReviewed = &t
+}
+
+// RecordStudy counts a review on the local date of t without recording it for a card
+func (s *State) RecordStudy(t time.Time) {
+	s.StudyDays[t.Format(dateLayout)]++
+}
+
+// RecordAnswer counts a correct or incorrect answer for a card given at time t
+func (s *State) RecordAnswer(id int, correct bool, t time.Time) {
+	stats := s.cardStats(id)
+	if correct {
+		stats.Correct++
+	} else {
+		stats.Incorrect++
+		stats.LastIncorrect = &t
+	}
+}
+
+// cardStats returns the statistics of a card, adding them if the card has none yet
+func (s *State) cardStats(id int) *CardStats {
+	if s.Cards == nil {
+		s.Cards = make(map[int]*CardStats)
+	}
+	stats := s.Cards[id]
+	if stats == nil {
+		stats = &CardStats{}
+		s.Cards[id] = stats
+	}
+	return stats
+}
+
+// Answers returns the total correct and incorrect answers over all cards
+func (s *State) Answers() CardStats {
+	var total CardStats
+	for _, stats := range s.Cards {
+		total.Correct += stats.Correct
+		total.Incorrect += stats.Incorrect
+	}
+	return total
+}
+
+// ReviewsOn returns the number of cards reviewed on the local date of t
+func (s *State) ReviewsOn(t time.Time) int {
+	return s.StudyDays[t.Format(dateLayout)]
+}
+
+// Streaks returns the current and longest runs of consecutive days with reviews.
+// The current streak is still alive if the last study day was yesterday, as
+// today may not be over yet.
+func (s *State) Streaks(now time.Time) (current, longest int) {
+	var days []time.Time
+	for date, reviews := range s.StudyDays {
+		if reviews <= 0 {
+			continue
+		}
+		day, err := time.ParseInLocation(dateLayout, date, now.Location())
+		if err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+
+	run := 0
+	for i, day := range days {
+		// Compare calendar dates rather than durations, days aren't 24h long across DST changes
+		if i > 0 && days[i-1].AddDate(0, 0, 1).Format(dateLayout) == day.Format(dateLayout) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+
+	if len(days) > 0 {
+		last := days[len(days)-1].Format(dateLayout)
+		today := now.Format(dateLayout)
+		yesterday := now.AddDate(0, 0, -1).Format(dateLayout)
+		if last == today || last == yesterday {
+			current = run
+		}
+	}
+	return current, longest
+}
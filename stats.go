@@ -0,0 +1,96 @@
+// stats.go
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// RecordReview counts a reviewed card towards today's study and saves the state
+func (a *App) RecordReview(id int) {
+	if a.keepsStats(id) {
+		a.State.RecordReview(id, time.Now())
+	} else {
+		a.State.RecordStudy(time.Now())
+	}
+	a.session.reviewed++
+	if err := a.State.Save(a.StateFile); err != nil {
+		a.Status = a.errorStatus("Error saving state", err)
+	}
+}
+
+// RecordAnswer counts an answer to a card towards its statistics and saves the state
+func (a *App) RecordAnswer(id int, correct bool) {
+	if !a.keepsStats(id) {
+		return
+	}
+	a.State.RecordAnswer(id, correct, time.Now())
+	if err := a.State.Save(a.StateFile); err != nil {
+		a.Status = a.errorStatus("Error saving state", err)
+	}
+}
+
+// streakBanner returns a message celebrating the current streak, or "" if there is none
+func (a *App) streakBanner() string {
+	current, _ := a.State.Streaks(time.Now())
+	if current < 2 {
+		return ""
+	}
+	return fmt.Sprintf("%d-day streak! Keep it going.", current)
+}
+
+// ShowStats displays study statistics, closed by any key
+func (a *App) ShowStats() {
+	now := time.Now()
+	current, longest := a.State.Streaks(now)
+
+	var total int
+	a.readDeck(func() {
+		total = len(a.Deck)
+	})
+
+	text := fmt.Sprintf(`
+[::b]Cards in deck:[::-]     %d
+[::b]Reviewed today:[::-]    %d
+[::b]Days studied:[::-]      %d
+[::b]Current streak:[::-]    %s
+[::b]Longest streak:[::-]    %s
+[::b]Quiz answers:[::-]      %s
+[::b]Confidence:[::-]        %s
+
+Press any key to close`,
+		total, a.State.ReviewsOn(now), len(a.State.StudyDays), days(current), days(longest), answers(a.State.Answers()),
+		confidenceSummary(a.State.AverageConfidence()))
+
+	stats := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(text)
+	stats.SetBorder(true).
+		SetTitle(" Statistics ").
+		SetTitleAlign(tview.AlignCenter)
+	stats.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		a.Application.SetRoot(a.MainView, true)
+		return nil
+	})
+
+	a.Application.SetRoot(centered(stats), true)
+}
+
+// answers formats the correct and incorrect answer counts
+func answers(stats CardStats) string {
+	if stats.Correct+stats.Incorrect == 0 {
+		return "none yet"
+	}
+	return fmt.Sprintf("%d correct, %d incorrect (%.0f%%)", stats.Correct, stats.Incorrect, 100*stats.Accuracy())
+}
+
+// days formats a number of days
+func days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
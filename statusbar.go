@@ -0,0 +1,44 @@
+// statusbar.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+// newStatusBar creates the one-line status bar shown under the card
+func newStatusBar() *tview.TextView {
+	return tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignRight)
+}
+
+// updateStatusBar shows the model, deck, card count and session token usage
+func (a *App) updateStatusBar() {
+	var total int
+	a.readDeck(func() {
+		total = len(a.Deck)
+	})
+
+	a.StatusBar.SetText(fmt.Sprintf("%s  |  %s  |  %d cards  |  %d tokens ",
+		tview.Escape(a.AI.Model), tview.Escape(a.deckName()), total, a.AI.TokensUsed()))
+}
+
+// ToggleStatusBar hides or shows the status bar and saves the preference
+func (a *App) ToggleStatusBar() {
+	a.Config.HideStatusBar = !a.Config.HideStatusBar
+	a.layoutStatusBar()
+	if err := SaveConfig(a.ConfigFile, a.Config); err != nil {
+		a.SetStatus(a.errorStatus("Error saving config", err))
+	}
+}
+
+// layoutStatusBar sizes the status bar according to the configuration
+func (a *App) layoutStatusBar() {
+	height := 1
+	if a.Config.HideStatusBar {
+		height = 0
+	}
+	a.MainView.ResizeItem(a.StatusBar, height, 0)
+}
@@ -0,0 +1,76 @@
+// strokes.go
+package main
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// bundledStrokes lists the characters by total stroke count
+//
+//go:embed strokes.txt
+var bundledStrokes string
+
+// strokeCounts maps characters to their total stroke count. The table is only parsed
+// the first time stroke counts are shown, so it costs nothing otherwise.
+var strokeCounts = sync.OnceValue(func() map[rune]int {
+	counts := make(map[rune]int)
+	for _, line := range strings.Split(bundledStrokes, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		count, characters, _ := strings.Cut(line, "\t")
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			panic("bundled stroke table: " + line)
+		}
+		for _, r := range characters {
+			counts[r] = n
+		}
+	}
+	return counts
+})
+
+// strokeSummary lists the stroke count of each Chinese character of the text and their
+// total, e.g. "你 7 · 好 6 = 13". Characters missing from the table count as "?", and
+// the total then ends in "+?". It returns "" if the text has no Chinese characters.
+func strokeSummary(text string) string {
+	counts := strokeCounts()
+	var parts []string
+	var total int
+	var unknown bool
+	for _, r := range text {
+		if !unicode.Is(unicode.Han, r) {
+			continue
+		}
+		n, ok := counts[r]
+		if !ok {
+			parts = append(parts, string(r)+" ?")
+			unknown = true
+			continue
+		}
+		parts = append(parts, string(r)+" "+strconv.Itoa(n))
+		total += n
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	sum := strconv.Itoa(total)
+	if unknown {
+		sum += "+?"
+	}
+	return strings.Join(parts, " · ") + " = " + sum
+}
+
+// ToggleStrokes shows or hides the stroke counts of the Chinese characters with the answer
+func (a *App) ToggleStrokes() {
+	a.ShowStrokes = !a.ShowStrokes
+	if a.ShowStrokes {
+		a.SetStatus("Showing stroke counts with the Chinese")
+	} else {
+		a.SetStatus("Stroke counts hidden")
+	}
+}
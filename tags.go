@@ -0,0 +1,37 @@
+// tags.go
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"chinese/pkg/chinese"
+)
+
+// ShowTagEditor displays a single line to edit the current card's tags, separated by commas.
+// Enter saves the tags and Escape leaves them as they were.
+func (a *App) ShowTagEditor() {
+	a.showLineEditor(" Tags (comma-separated) ", "Tags: ", func(card chinese.Flashcard) string {
+		return strings.Join(card.Tags, ", ")
+	}, func(card *chinese.Flashcard, text string) string {
+		tags := parseTags(text)
+		if slices.Equal(tags, card.Tags) {
+			return ""
+		}
+		card.Tags = tags
+		return fmt.Sprintf("Card %d has %d tags", card.ID, len(card.Tags))
+	})
+}
+
+// parseTags splits comma-separated tags, dropping empty and repeated ones
+func parseTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,75 @@
+// theme.go
+package main
+
+// Theme holds the colors used to render a card, as tview color names
+type Theme struct {
+	English string `json:"english,omitempty"`
+	Chinese string `json:"chinese,omitempty"`
+	Pinyin  string `json:"pinyin,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Label   string `json:"label,omitempty"`
+	Border  string `json:"border,omitempty"`
+}
+
+// DefaultThemeName is the theme used when none is configured
+const DefaultThemeName = "dark"
+
+// Themes contains the built-in theme presets, selectable by name
+var Themes = map[string]Theme{
+	"dark": {
+		English: "cyan",
+		Chinese: "yellow",
+		Pinyin:  "green",
+		Text:    "white",
+		Border:  "white",
+	},
+	"light": {
+		English: "navy",
+		Chinese: "maroon",
+		Pinyin:  "darkgreen",
+		Text:    "black",
+		Label:   "black",
+		Border:  "black",
+	},
+	"high-contrast": {
+		English: "aqua",
+		Chinese: "yellow",
+		Pinyin:  "lime",
+		Text:    "white",
+		Label:   "white",
+		Border:  "yellow",
+	},
+}
+
+// Merge returns a copy of the theme with the non-empty colors of other applied on top
+func (t Theme) Merge(other Theme) Theme {
+	if other.English != "" {
+		t.English = other.English
+	}
+	if other.Chinese != "" {
+		t.Chinese = other.Chinese
+	}
+	if other.Pinyin != "" {
+		t.Pinyin = other.Pinyin
+	}
+	if other.Text != "" {
+		t.Text = other.Text
+	}
+	if other.Label != "" {
+		t.Label = other.Label
+	}
+	if other.Border != "" {
+		t.Border = other.Border
+	}
+	return t
+}
+
+// Color wraps text in a tview color tag, resetting to the theme's text color afterwards
+func (t Theme) Color(color, text string) string {
+	return "[" + color + "]" + text + "[" + t.Text + "]"
+}
+
+// LabelTag formats a bold field label
+func (t Theme) LabelTag(text string) string {
+	return "[" + t.Label + "::b]" + text + "[" + t.Text + "::-]"
+}
@@ -0,0 +1,145 @@
+// tones.go
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/tview"
+)
+
+// TonePalette holds the colors of the four tones followed by the neutral tone,
+// as tview color names
+type TonePalette [5]string
+
+// TonePalettes contains the palettes selectable with tone_colors in the config.
+// Every place that colors Pinyin by tone draws from here.
+var TonePalettes = map[string]TonePalette{
+	// The common red, green, blue and purple scheme of dictionary apps
+	"standard": {"#ff3030", "#30c030", "#3080ff", "#b050e0", "gray"},
+	// Blue, orange, yellow and reddish purple from the Okabe-Ito palette, which stay
+	// distinct with red-green color blindness
+	"deuteranopia": {"#0072b2", "#e69f00", "#f0e442", "#cc79a7", "gray"},
+}
+
+// toneMarks maps vowels with a tone mark to their tone
+var toneMarks = map[rune]int{
+	'ā': 1, 'á': 2, 'ǎ': 3, 'à': 4,
+	'ē': 1, 'é': 2, 'ě': 3, 'è': 4,
+	'ī': 1, 'í': 2, 'ǐ': 3, 'ì': 4,
+	'ō': 1, 'ó': 2, 'ǒ': 3, 'ò': 4,
+	'ū': 1, 'ú': 2, 'ǔ': 3, 'ù': 4,
+	'ǖ': 1, 'ǘ': 2, 'ǚ': 3, 'ǜ': 4,
+}
+
+// toneless removes the tone marks of Pinyin, e.g. "nǐ hǎo" becomes "ni hao"
+var toneless = strings.NewReplacer(
+	"ā", "a", "á", "a", "ǎ", "a", "à", "a",
+	"ē", "e", "é", "e", "ě", "e", "è", "e",
+	"ī", "i", "í", "i", "ǐ", "i", "ì", "i",
+	"ō", "o", "ó", "o", "ǒ", "o", "ò", "o",
+	"ū", "u", "ú", "u", "ǔ", "u", "ù", "u",
+	"ǖ", "ü", "ǘ", "ü", "ǚ", "ü", "ǜ", "ü",
+).Replace
+
+// pinyinText renders revealed Pinyin, colored by tone if tone_colors is set and in the
+// theme's Pinyin color otherwise
+func (a *App) pinyinText(text string) string {
+	if a.hidesPinyin() {
+		return a.Theme.Color(a.Theme.Pinyin, tview.Escape(mask(text)))
+	}
+	palette, ok := TonePalettes[a.Config.ToneColors]
+	if !ok {
+		return a.Theme.Color(a.Theme.Pinyin, tview.Escape(text))
+	}
+	return colorTones(text, palette, a.Theme.Text)
+}
+
+// colorTones wraps each syllable of the Pinyin in the color of its tone, ending with
+// the given text color. Text between the words is kept as it is.
+func colorTones(text string, palette TonePalette, textColor string) string {
+	var b strings.Builder
+	var word []rune
+	flush := func() {
+		for _, syllable := range splitSyllables(word) {
+			b.WriteString("[" + palette[syllableTone(syllable)-1] + "]")
+			b.WriteString(tview.Escape(string(syllable)))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		b.WriteString("[" + textColor + "]" + tview.Escape(string(r)))
+	}
+	flush()
+	b.WriteString("[" + textColor + "]")
+	return b.String()
+}
+
+// splitSyllables splits a Pinyin word such as "zhōngguó" into its syllables. A syllable
+// ends before a consonant once it has a vowel, unless the consonant is a final n, ng or r.
+// Words without apostrophes can be ambiguous, e.g. "fangan", but such syllables are rare.
+func splitSyllables(word []rune) [][]rune {
+	var syllables [][]rune
+	start, hasVowel := 0, false
+	for i, r := range word {
+		if isPinyinVowel(r) {
+			hasVowel = true
+			continue
+		}
+		if !hasVowel || isFinal(word, i) {
+			continue
+		}
+		syllables = append(syllables, word[start:i])
+		start, hasVowel = i, false
+	}
+	if start < len(word) {
+		syllables = append(syllables, word[start:])
+	}
+	return syllables
+}
+
+// countSyllables returns the number of Pinyin syllables in a text, ignoring punctuation
+// and anything else between the words
+func countSyllables(text string) int {
+	var count int
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		count += len(splitSyllables([]rune(word)))
+	}
+	return count
+}
+
+// isFinal reports whether the consonant at position i ends the syllable before it,
+// as the n of "ān", the ng of "zhōng" or the r of "huàr"
+func isFinal(word []rune, i int) bool {
+	beforeVowel := i+1 < len(word) && isPinyinVowel(word[i+1])
+	switch unicode.ToLower(word[i]) {
+	case 'n':
+		return !beforeVowel
+	case 'g':
+		return i > 0 && unicode.ToLower(word[i-1]) == 'n' && !beforeVowel
+	case 'r':
+		return i+1 == len(word)
+	}
+	return false
+}
+
+// isPinyinVowel reports whether the letter is a Pinyin vowel, with or without a tone mark
+func isPinyinVowel(r rune) bool {
+	r = unicode.ToLower(r)
+	return strings.ContainsRune("aeiouüv", r) || toneMarks[r] != 0
+}
+
+// syllableTone returns the tone of a syllable from its tone mark, 5 for the neutral tone
+func syllableTone(syllable []rune) int {
+	for _, r := range syllable {
+		if tone := toneMarks[unicode.ToLower(r)]; tone != 0 {
+			return tone
+		}
+	}
+	return 5
+}
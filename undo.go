@@ -0,0 +1,100 @@
+// undo.go
+package main
+
+import (
+	"errors"
+
+	"chinese/pkg/chinese"
+)
+
+// historyLimit caps the number of deck states kept for undo and redo
+const historyLimit = 50
+
+// ErrNothingToUndo is returned when there is no change to undo or redo
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrNothingToRedo is returned when there is no undone change to redo
+var ErrNothingToRedo = errors.New("nothing to redo")
+
+// snapshot records the current deck before a mutation so it can be undone.
+// Any undone changes can no longer be redone. The caller must hold a.mu.
+func (a *App) snapshot() {
+	a.undoStack = pushHistory(a.undoStack, a.Deck)
+	a.redoStack = nil
+}
+
+// Undo restores the deck as it was before the last change and rewrites the file
+func (a *App) Undo() error {
+	return a.mutateDeck(func() error {
+		if err := a.checkWritable(); err != nil {
+			return err
+		}
+		if len(a.undoStack) == 0 {
+			return ErrNothingToUndo
+		}
+		a.redoStack = pushHistory(a.redoStack, a.Deck)
+		a.Deck, a.undoStack = popHistory(a.undoStack)
+		a.clampCurrentCard()
+		return a.rewriteDeck()
+	})
+}
+
+// Redo reapplies the last undone change and rewrites the file
+func (a *App) Redo() error {
+	return a.mutateDeck(func() error {
+		if err := a.checkWritable(); err != nil {
+			return err
+		}
+		if len(a.redoStack) == 0 {
+			return ErrNothingToRedo
+		}
+		a.undoStack = pushHistory(a.undoStack, a.Deck)
+		a.Deck, a.redoStack = popHistory(a.redoStack)
+		a.clampCurrentCard()
+		return a.rewriteDeck()
+	})
+}
+
+// clampCurrentCard keeps the current card index within the deck. The caller must hold a.mu.
+func (a *App) clampCurrentCard() {
+	if a.CurrentCardIdx >= len(a.Deck) {
+		a.CurrentCardIdx = max(len(a.Deck)-1, 0)
+	}
+}
+
+// pushHistory appends a copy of the deck to the stack, dropping the oldest entry when full
+func pushHistory(stack [][]chinese.Flashcard, deck []chinese.Flashcard) [][]chinese.Flashcard {
+	state := make([]chinese.Flashcard, len(deck))
+	copy(state, deck)
+	stack = append(stack, state)
+	if len(stack) > historyLimit {
+		stack = stack[1:]
+	}
+	return stack
+}
+
+// popHistory removes and returns the most recent deck from the stack
+func popHistory(stack [][]chinese.Flashcard) ([]chinese.Flashcard, [][]chinese.Flashcard) {
+	last := len(stack) - 1
+	return stack[last], stack[:last]
+}
+
+// UndoChange undoes the last change to the deck and reports the result
+func (a *App) UndoChange() {
+	if err := a.Undo(); err != nil {
+		a.SetStatus("Undo: " + err.Error())
+		return
+	}
+	a.RevealStep = 0
+	a.SetStatus("Undid the last change")
+}
+
+// RedoChange redoes the last undone change and reports the result
+func (a *App) RedoChange() {
+	if err := a.Redo(); err != nil {
+		a.SetStatus("Redo: " + err.Error())
+		return
+	}
+	a.RevealStep = 0
+	a.SetStatus("Redid the last undone change")
+}
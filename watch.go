@@ -0,0 +1,227 @@
+// watch.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"chinese/pkg/chinese"
+)
+
+// watchInterval is how often --watch checks the flashcards file for changes
+const watchInterval = time.Second
+
+// fileStamp identifies a version of a file by its modification time and size
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the stamp of a file, or the zero stamp if it doesn't exist
+func statFile(filename string) fileStamp {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{info.ModTime(), info.Size()}
+}
+
+// noteWritten records the stamp and the cards of the flashcards file after the app loaded
+// or wrote it, so that watching the file doesn't reload the app's own changes. The cards
+// must not be changed afterwards. The caller must hold a.mu.
+func (a *App) noteWritten(saved []chinese.Flashcard) {
+	a.fileStamp = statFile(a.FlashcardsFile)
+	a.savedDeck = saved
+}
+
+// WatchDeck checks the flashcards file for changes made by other programs, such as a
+// text editor, every watchInterval and reloads the deck when it changes. The reload waits
+// until the main view is shown again if a dialog is open.
+func (a *App) WatchDeck() {
+	go func() {
+		defer a.recoverPanic()
+		pending := false
+		for range time.Tick(watchInterval) {
+			a.mutateDeck(func() error {
+				// Taken under the lock so the app's own writes are noted before or after it
+				stamp := statFile(a.FlashcardsFile)
+				// A missing file is usually an editor halfway through saving it
+				if stamp != a.fileStamp && stamp != (fileStamp{}) {
+					a.fileStamp = stamp
+					pending = true
+				}
+				return nil
+			})
+			if !pending {
+				continue
+			}
+
+			reloaded := make(chan bool, 1)
+			a.Application.QueueUpdateDraw(func() {
+				if a.Application.GetFocus() != a.CardView {
+					reloaded <- false
+					return
+				}
+				reloaded <- true
+				// The file is read now, as the app may have written it while the reload waited
+				cards, err := readCards(a.FlashcardsFile, a.Config.FieldNames)
+				if err != nil {
+					a.SetStatus(a.errorStatus("Error reloading the changed flashcards file", err))
+					return
+				}
+				a.reloadDeck(cards)
+			})
+			pending = !<-reloaded
+		}
+	}()
+}
+
+// reloadDeck replaces the deck with the cards read from the changed flashcards file.
+// Changes that couldn't be saved are merged in; if some cards were also changed in the
+// file, the user chooses which version to keep.
+func (a *App) reloadDeck(cards []chinese.Flashcard) {
+	var dirty bool
+	var conflicts int
+	a.readDeck(func() {
+		dirty = a.dirty
+		if dirty {
+			_, conflicts = mergeUnsaved(a.savedDeck, cards, a.Deck, false)
+		}
+	})
+
+	reload := func(preferFile bool) {
+		changed := false
+		err := a.mutateDeck(func() error {
+			deck := cards
+			if dirty {
+				deck, _ = mergeUnsaved(a.savedDeck, cards, a.Deck, preferFile)
+			}
+			a.savedDeck = slices.Clone(cards)
+			changed = a.replaceDeck(deck)
+			if dirty {
+				// The file has the external changes but not the merged ones yet
+				return a.rewriteDeck()
+			}
+			return nil
+		})
+		if err != nil {
+			a.SetStatus(a.errorStatus("Error saving the reloaded deck", err))
+			return
+		}
+		if !changed {
+			return
+		}
+		a.Logger.Info("deck reloaded", "file", a.FlashcardsFile, "cards", len(cards))
+		a.SetStatus(fmt.Sprintf("Reloaded the deck, the flashcards file changed (%d cards)", len(cards)))
+	}
+	if conflicts == 0 {
+		reload(false)
+		return
+	}
+
+	changed := fmt.Sprintf("%d cards were", conflicts)
+	if conflicts == 1 {
+		changed = "1 card was"
+	}
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("The flashcards file changed. %s also changed here, where the changes couldn't be saved. Which version should be kept?", changed)).
+		AddButtons([]string{"Keep the file's", "Keep mine"}).
+		SetDoneFunc(func(_ int, label string) {
+			a.Application.SetRoot(a.MainView, true)
+			reload(label == "Keep the file's")
+		})
+	a.Application.SetRoot(modal, true)
+}
+
+// replaceDeck replaces the deck, staying on the current card if it is still there, and
+// reports whether any card changed. The previous deck can then be restored with undo.
+// The caller must hold a.mu.
+func (a *App) replaceDeck(cards []chinese.Flashcard) bool {
+	if slices.EqualFunc(a.Deck, cards, sameCard) {
+		return false
+	}
+	a.snapshot()
+	var current int
+	if len(a.Deck) > 0 {
+		current = a.Deck[a.CurrentCardIdx].ID
+	}
+	a.Deck = cards
+	if idx := a.cardIndex(current); idx >= 0 {
+		a.CurrentCardIdx = idx
+	}
+	a.clampCurrentCard()
+	a.queue.sync(a.Deck)
+	return true
+}
+
+// mergeUnsaved merges the changes to the deck in memory that couldn't be saved with the
+// changes to the file, both made since base, the cards of the file as last loaded or
+// written, matching cards by ID. A card changed or deleted on one side only takes that
+// side's change. A card changed differently on both sides is a conflict, resolved with
+// the file's version if preferFile is set and the version in memory otherwise. Cards
+// follow the order of the file, with cards only in memory at the end.
+func mergeUnsaved(base, file, memory []chinese.Flashcard, preferFile bool) ([]chinese.Flashcard, int) {
+	inBase, inFile, inMemory := cardsByID(base), cardsByID(file), cardsByID(memory)
+
+	var conflicts int
+	// pick returns the merged version of the card with the given ID, if it is kept
+	pick := func(id int) (chinese.Flashcard, bool) {
+		b, wasSaved := inBase[id]
+		f, okFile := inFile[id]
+		m, okMemory := inMemory[id]
+		fileChanged := okFile != wasSaved || okFile && !sameCard(f, b)
+		memoryChanged := okMemory != wasSaved || okMemory && !sameCard(m, b)
+		switch {
+		case !memoryChanged:
+			return f, okFile
+		case !fileChanged:
+			return m, okMemory
+		case okFile == okMemory && (!okFile || sameCard(f, m)):
+			// The same change was made on both sides
+			return f, okFile
+		}
+		conflicts++
+		if preferFile {
+			return f, okFile
+		}
+		return m, okMemory
+	}
+
+	merged := make([]chinese.Flashcard, 0, len(file))
+	for _, card := range file {
+		if card, ok := pick(card.ID); ok {
+			merged = append(merged, card)
+		}
+	}
+	for _, card := range memory {
+		if _, ok := inFile[card.ID]; ok {
+			continue
+		}
+		if card, ok := pick(card.ID); ok {
+			merged = append(merged, card)
+		}
+	}
+	return merged, conflicts
+}
+
+// cardsByID indexes the cards by ID
+func cardsByID(cards []chinese.Flashcard) map[int]chinese.Flashcard {
+	byID := make(map[int]chinese.Flashcard, len(cards))
+	for _, card := range cards {
+		byID[card.ID] = card
+	}
+	return byID
+}
+
+// sameCard reports whether two cards would be saved the same
+func sameCard(x, y chinese.Flashcard) bool {
+	xJSON, errX := json.Marshal(x)
+	yJSON, errY := json.Marshal(y)
+	return errX == nil && errY == nil && bytes.Equal(xJSON, yJSON)
+}
@@ -0,0 +1,125 @@
+// watch_test.go
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"chinese/pkg/chinese"
+)
+
+func TestMergeUnsaved(t *testing.T) {
+	card := func(id int, english string) chinese.Flashcard {
+		return chinese.Flashcard{ID: id, English: english}
+	}
+	// describe lists the cards as id:English for comparison
+	describe := func(cards []chinese.Flashcard) string {
+		var parts []string
+		for _, c := range cards {
+			parts = append(parts, fmt.Sprintf("%d:%s", c.ID, c.English))
+		}
+		return strings.Join(parts, " ")
+	}
+	base := []chinese.Flashcard{card(1, "one"), card(2, "two"), card(3, "three")}
+
+	tests := []struct {
+		name          string
+		file, memory  []chinese.Flashcard
+		preferFile    bool
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:   "changed in the file only",
+			file:   []chinese.Flashcard{card(1, "uno"), card(2, "two"), card(3, "three")},
+			memory: base,
+			want:   "1:uno 2:two 3:three",
+		},
+		{
+			name:   "changed in memory only",
+			file:   base,
+			memory: []chinese.Flashcard{card(1, "one"), card(2, "dos"), card(3, "three")},
+			want:   "1:one 2:dos 3:three",
+		},
+		{
+			name:   "different cards changed on each side",
+			file:   []chinese.Flashcard{card(1, "uno"), card(2, "two"), card(3, "three")},
+			memory: []chinese.Flashcard{card(1, "one"), card(2, "dos"), card(3, "three")},
+			want:   "1:uno 2:dos 3:three",
+		},
+		{
+			name:   "deleted in the file",
+			file:   []chinese.Flashcard{card(1, "one"), card(3, "three")},
+			memory: []chinese.Flashcard{card(1, "one"), card(2, "two"), card(3, "three"), card(4, "four")},
+			want:   "1:one 3:three 4:four",
+		},
+		{
+			name:   "deleted in memory",
+			file:   []chinese.Flashcard{card(1, "uno"), card(2, "two"), card(3, "three")},
+			memory: []chinese.Flashcard{card(1, "one"), card(3, "three")},
+			want:   "1:uno 3:three",
+		},
+		{
+			name:   "added on both sides",
+			file:   []chinese.Flashcard{card(1, "one"), card(2, "two"), card(3, "three"), card(5, "five")},
+			memory: []chinese.Flashcard{card(1, "one"), card(2, "two"), card(3, "three"), card(4, "four")},
+			want:   "1:one 2:two 3:three 5:five 4:four",
+		},
+		{
+			name:   "same change on both sides",
+			file:   []chinese.Flashcard{card(1, "uno"), card(2, "two"), card(3, "three")},
+			memory: []chinese.Flashcard{card(1, "uno"), card(2, "two"), card(3, "three")},
+			want:   "1:uno 2:two 3:three",
+		},
+		{
+			name:          "conflict keeping mine",
+			file:          []chinese.Flashcard{card(1, "uno"), card(2, "two"), card(3, "three")},
+			memory:        []chinese.Flashcard{card(1, "un"), card(2, "two"), card(3, "three")},
+			want:          "1:un 2:two 3:three",
+			wantConflicts: 1,
+		},
+		{
+			name:          "conflict keeping the file's",
+			file:          []chinese.Flashcard{card(1, "uno"), card(2, "two"), card(3, "three")},
+			memory:        []chinese.Flashcard{card(1, "un"), card(2, "two"), card(3, "three")},
+			preferFile:    true,
+			want:          "1:uno 2:two 3:three",
+			wantConflicts: 1,
+		},
+		{
+			name:          "deleted in the file and changed in memory",
+			file:          []chinese.Flashcard{card(1, "one"), card(3, "three")},
+			memory:        []chinese.Flashcard{card(1, "one"), card(2, "dos"), card(3, "three")},
+			want:          "1:one 3:three 2:dos",
+			wantConflicts: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicts := mergeUnsaved(base, test.file, test.memory, test.preferFile)
+			if got := describe(merged); got != test.want || conflicts != test.wantConflicts {
+				t.Errorf("mergeUnsaved = %q with %d conflicts, want %q with %d", got, conflicts, test.want, test.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestReplaceDeckUndo(t *testing.T) {
+	a := NewApp("test-key", "test-model")
+	a.Deck = []chinese.Flashcard{{ID: 1, English: "one"}, {ID: 2, English: "two"}}
+
+	if a.replaceDeck([]chinese.Flashcard{{ID: 1, English: "one"}, {ID: 2, English: "two"}}) {
+		t.Error("replaceDeck reported a change for the same cards")
+	}
+	if len(a.undoStack) != 0 {
+		t.Errorf("replacing the deck with the same cards added %d undo entries", len(a.undoStack))
+	}
+
+	if !a.replaceDeck([]chinese.Flashcard{{ID: 1, English: "uno"}, {ID: 2, English: "two"}}) {
+		t.Error("replaceDeck reported no change for a changed card")
+	}
+	if len(a.undoStack) != 1 {
+		t.Errorf("replacing the deck with a changed card added %d undo entries, want 1", len(a.undoStack))
+	}
+}
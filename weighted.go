@@ -0,0 +1,159 @@
+// weighted.go
+package main
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Default weak card weighting, used when the config leaves them unset
+const (
+	defaultWeakAccuracyWeight   = 4
+	defaultWeakRecentWeight     = 4
+	defaultWeakRecentDays       = 7
+	defaultWeakConfidenceWeight = 4
+)
+
+// defaultStaleDecayDays is how many days without a review add 1 to a card's weight in
+// stale card focus, used when the config leaves it unset
+const defaultStaleDecayDays = 7
+
+// neverReviewedDays is how long ago a card that was never reviewed and has no creation
+// date is treated as reviewed, so such cards rank with the stalest ones
+const neverReviewedDays = 365
+
+// weightedOrder picks the next card at random, favoring cards with a higher weight.
+// A card's chance also grows with the number of cards shown since it was last shown,
+// so every card comes up eventually however low its weight.
+type weightedOrder struct {
+	// mode names the focus mode using the order, e.g. "Weak card focus"
+	mode string
+
+	// weight returns the weight of a card by ID, at least 1
+	weight func(id int) float64
+
+	// pinned reports whether a card is pinned, by ID
+	pinned func(id int) bool
+
+	// turn counts the cards shown, shown records the turn each card was last shown at
+	turn  int
+	shown map[int]int
+}
+
+// newWeightedOrder creates a weighted order with the given mode name, weight function
+// and pinned card test
+func newWeightedOrder(mode string, weight func(id int) float64, pinned func(id int) bool) *weightedOrder {
+	return &weightedOrder{mode: mode, weight: weight, pinned: pinned, shown: make(map[int]int)}
+}
+
+// next returns the card to show after the current one, among the queued cards.
+// Pinned cards not shown yet come first, in queue order.
+func (o *weightedOrder) next(ids []int, current int) int {
+	o.turn++
+	o.shown[current] = o.turn
+	if len(ids) == 0 {
+		return current
+	}
+	for _, id := range ids {
+		if id != current && o.shown[id] == 0 && o.pinned(id) {
+			return id
+		}
+	}
+
+	weights := make([]float64, len(ids))
+	var total float64
+	for i, id := range ids {
+		if id == current && len(ids) > 1 {
+			continue
+		}
+		// Waiting a whole round of the queue doubles a card's chance
+		waited := o.turn - o.shown[id]
+		weights[i] = o.weight(id) * (1 + float64(waited)/float64(len(ids)))
+		total += weights[i]
+	}
+
+	r := rand.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return ids[i]
+		}
+		r -= w
+	}
+	return ids[len(ids)-1]
+}
+
+// ToggleWeakCards switches between the review queue order and showing weak cards more often
+func (a *App) ToggleWeakCards() {
+	a.toggleOrder("Weak card focus", a.weakWeight, "cards answered wrongly in the quiz or rated with a low confidence come up more often")
+}
+
+// ToggleStaleCards switches between the review queue order and showing the cards that
+// haven't been reviewed for longest more often
+func (a *App) ToggleStaleCards() {
+	a.toggleOrder("Stale card focus", a.staleWeight, "cards not reviewed for a while come up more often")
+}
+
+// toggleOrder turns a focus mode on, replacing any other, or off if it is already on
+func (a *App) toggleOrder(mode string, weight func(id int) float64, description string) {
+	if a.order != nil && a.order.mode == mode {
+		a.order = nil
+		a.SetStatus(mode + " off, cards come up in order")
+		return
+	}
+	a.order = newWeightedOrder(mode, weight, a.isPinned)
+	a.SetStatus(mode + " on, " + description)
+}
+
+// weakWeight weights a card by how poorly it was answered in the quiz: cards with a low
+// accuracy, cards answered wrongly in the last few days and cards last rated with a low
+// confidence get a higher weight
+func (a *App) weakWeight(id int) float64 {
+	weight := 1.0
+	stats := a.cardStats(id)
+	if stats == nil {
+		return weight
+	}
+
+	accuracyWeight, recentWeight, recentDays := a.Config.WeakAccuracyWeight, a.Config.WeakRecentWeight, a.Config.WeakRecentDays
+	confidenceWeight := a.Config.WeakConfidenceWeight
+	if accuracyWeight == 0 {
+		accuracyWeight = defaultWeakAccuracyWeight
+	}
+	if recentWeight == 0 {
+		recentWeight = defaultWeakRecentWeight
+	}
+	if recentDays == 0 {
+		recentDays = defaultWeakRecentDays
+	}
+	if confidenceWeight == 0 {
+		confidenceWeight = defaultWeakConfidenceWeight
+	}
+
+	if stats.Correct+stats.Incorrect > 0 {
+		weight += accuracyWeight * (1 - stats.Accuracy())
+	}
+	if stats.LastIncorrect != nil && time.Since(*stats.LastIncorrect) < time.Duration(recentDays)*24*time.Hour {
+		weight += recentWeight
+	}
+	if confidence := stats.Confidence(); confidence > 0 {
+		weight += confidenceWeight * float64(maxConfidence-confidence) / (maxConfidence - 1)
+	}
+	return weight
+}
+
+// staleWeight weights a card by how long ago it was last reviewed, adding 1 for every
+// stale_decay_days days. Cards never reviewed count from when they were added.
+func (a *App) staleWeight(id int) float64 {
+	decayDays := a.Config.StaleDecayDays
+	if decayDays == 0 {
+		decayDays = defaultStaleDecayDays
+	}
+
+	days := float64(neverReviewedDays)
+	if stats := a.cardStats(id); stats != nil && stats.LastReviewed != nil {
+		days = time.Since(*stats.LastReviewed).Hours() / 24
+	} else if idx := a.cardIndex(id); idx >= 0 && a.Deck[idx].CreatedAt != nil {
+		days = time.Since(*a.Deck[idx].CreatedAt).Hours() / 24
+	}
+	return 1 + max(days, 0)/decayDays
+}
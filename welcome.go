@@ -0,0 +1,57 @@
+// welcome.go
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ShowWelcome displays an introduction for new users with an empty deck, closed by any key
+func (a *App) ShowWelcome() {
+	// New cards of an empty combined deck go to its first file
+	filename := a.FlashcardsFile
+	if a.DeckFiles != nil {
+		filename = a.DeckFiles[0]
+	}
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		path = filename
+	}
+
+	text := fmt.Sprintf(`[::b]Welcome to Chinese Learning Cards![::-]
+
+Your deck is empty. Press [::b]n[::-] to add a card: type an English sentence and it is translated into Chinese characters and Pinyin for you.
+
+Cards are saved to %s
+
+Translations use the OpenAI API, so an API key is required (--api-key or OPENAI_API_KEY).
+
+Press [::b]?[::-] at any time to see all controls, or any key to continue.`, tview.Escape(path))
+
+	welcome := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true).
+		SetWordWrap(true).
+		SetText(text)
+	welcome.SetBorder(true).
+		SetTitle(" Welcome ").
+		SetTitleAlign(tview.AlignCenter)
+	welcome.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		a.Application.SetRoot(a.MainView, true)
+		return nil
+	})
+
+	// Use the same layout as the card view, the text doesn't fit in a regular dialog
+	layout := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(welcome, 0, 2, true).
+			AddItem(nil, 0, 1, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	a.Application.SetRoot(layout, true)
+}